@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"net/http"
+	"time"
+)
+
+// Entry is a cached response
+type Entry struct {
+	Body    []byte
+	Header  http.Header
+	ETag    string
+	Tags    []string
+	Expires time.Time
+}
+
+func (e Entry) Expired() bool {
+	return !e.Expires.IsZero() && time.Now().After(e.Expires)
+}
+
+type Store interface {
+	// Get returns the entry of the key unless it's missing or expired
+	Get(key string) (Entry, bool)
+	Set(key string, entry Entry)
+	// Invalidate removes the entries that have any of the tags
+	Invalidate(tags ...string)
+}
+
+var store Store = NewMemoryStore(1000)
+
+// SetStore changes the store where pages are cached, by default an in memory LRU store of 1000 entries is used
+func SetStore(s Store) {
+	store = s
+}
+
+// Default returns the store where pages are cached
+func Default() Store {
+	return store
+}
+
+// Invalidate removes the cached pages that have any of the tags
+func Invalidate(tags ...string) {
+	store.Invalidate(tags...)
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"container/list"
+	"slices"
+	"sync"
+)
+
+type memoryItem struct {
+	key   string
+	entry Entry
+}
+
+// MemoryStore is an in memory store that evicts the least recently used entries once it's full
+type MemoryStore struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+func NewMemoryStore(capacity int) *MemoryStore {
+	return &MemoryStore{
+		capacity: capacity,
+		items:    map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+func (m *MemoryStore) Get(key string) (Entry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	element, ok := m.items[key]
+	if !ok {
+		return Entry{}, false
+	}
+	item := element.Value.(*memoryItem)
+	if item.entry.Expired() {
+		m.remove(element)
+		return Entry{}, false
+	}
+	m.order.MoveToFront(element)
+	return item.entry, true
+}
+
+func (m *MemoryStore) Set(key string, entry Entry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if element, ok := m.items[key]; ok {
+		element.Value.(*memoryItem).entry = entry
+		m.order.MoveToFront(element)
+		return
+	}
+	m.items[key] = m.order.PushFront(&memoryItem{key: key, entry: entry})
+	for m.capacity > 0 && m.order.Len() > m.capacity {
+		m.remove(m.order.Back())
+	}
+}
+
+func (m *MemoryStore) Invalidate(tags ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for element := m.order.Front(); element != nil; {
+		next := element.Next()
+		for _, tag := range element.Value.(*memoryItem).entry.Tags {
+			if slices.Contains(tags, tag) {
+				m.remove(element)
+				break
+			}
+		}
+		element = next
+	}
+}
+
+func (m *MemoryStore) remove(element *list.Element) {
+	m.order.Remove(element)
+	delete(m.items, element.Value.(*memoryItem).key)
+}
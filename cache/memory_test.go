@@ -0,0 +1,31 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore(2)
+	store.Set("a", Entry{Body: []byte("a"), Tags: []string{"posts"}})
+	store.Set("b", Entry{Body: []byte("b"), Tags: []string{"users"}})
+
+	// reading a makes b the least recently used one
+	_, ok := store.Get("a")
+	assert.True(t, ok)
+	store.Set("c", Entry{Body: []byte("c"), Tags: []string{"posts", "users"}})
+	_, ok = store.Get("b")
+	assert.False(t, ok)
+
+	store.Invalidate("posts")
+	_, ok = store.Get("a")
+	assert.False(t, ok)
+	_, ok = store.Get("c")
+	assert.False(t, ok)
+
+	store.Set("expired", Entry{Expires: time.Now().Add(-time.Second)})
+	_, ok = store.Get("expired")
+	assert.False(t, ok)
+}
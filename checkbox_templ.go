@@ -18,7 +18,7 @@ type CheckboxProps struct {
 func checkboxLabelClass() templ.CSSClass {
 	templ_7745c5c3_CSSBuilder := templruntime.GetBuilder()
 	templ_7745c5c3_CSSBuilder.WriteString(`display:flex;`)
-	templ_7745c5c3_CSSBuilder.WriteString(`gap:0.25rem;`)
+	templ_7745c5c3_CSSBuilder.WriteString(`gap:var(--spacing, 0.25rem);`)
 	templ_7745c5c3_CSSBuilder.WriteString(`font-weight:800;`)
 	templ_7745c5c3_CSSBuilder.WriteString(`font-size:0.875rem;`)
 	templ_7745c5c3_CSSID := templ.CSSID(`checkboxLabelClass`, templ_7745c5c3_CSSBuilder.String())
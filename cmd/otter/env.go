@@ -0,0 +1,243 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/martinmunillas/otter/log"
+	"github.com/spf13/cobra"
+)
+
+const envPackagePath = "github.com/martinmunillas/otter/env"
+
+func init() {
+	envCheckCmd.Flags().String("env-file", ".env", "Env file to check")
+	envCheckCmd.Flags().String("example-file", ".env.example", "Env file used as reference")
+	envCheckCmd.Flags().Bool("scan", false, "Scan the go files of the project for required env variables")
+	envCmd.AddCommand(envCheckCmd)
+}
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Env variables utils",
+	Long:  ``,
+}
+
+var envCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Compares your .env against .env.example",
+	Long: `Reports the variables of .env.example missing on .env, the ones on .env that aren't on .env.example and the empty values of required variables.
+The variables of .env.example are required when they have an example value or a "# required" comment, either on the same line or the one above.
+With --scan it also looks for env.RequiredStringEnvVar, env.RequiredIntEnvVar and required env struct tags on the project and reports the variables that are in neither file.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := log.NewLogger(false)
+		envFile, _ := cmd.Flags().GetString("env-file")
+		exampleFile, _ := cmd.Flags().GetString("example-file")
+		scan, _ := cmd.Flags().GetBool("scan")
+
+		example, err := godotenv.Read(exampleFile)
+		if err != nil {
+			fatal(logger, fmt.Errorf("error reading %s: %w", exampleFile, err))
+		}
+		vars, err := godotenv.Read(envFile)
+		if errors.Is(err, os.ErrNotExist) {
+			vars = map[string]string{}
+		} else if err != nil {
+			fatal(logger, fmt.Errorf("error reading %s: %w", envFile, err))
+		}
+
+		required, err := requiredExampleVars(exampleFile, example)
+		if err != nil {
+			fatal(logger, fmt.Errorf("error reading %s: %w", exampleFile, err))
+		}
+		scanned := map[string]bool{}
+		if scan {
+			scanned, err = scanRequiredEnvVars(".")
+			if err != nil {
+				fatal(logger, fmt.Errorf("error scanning project: %w", err))
+			}
+			for key := range scanned {
+				required[key] = true
+			}
+		}
+
+		failed := false
+		for _, key := range sortedKeys(example) {
+			if _, ok := vars[key]; !ok {
+				failed = true
+				logger.Error(fmt.Sprintf("`%s` is missing on %s", key, envFile))
+			}
+		}
+		for _, key := range sortedKeys(vars) {
+			if _, ok := example[key]; !ok {
+				logger.Warn(fmt.Sprintf("`%s` is not on %s", key, exampleFile))
+			}
+			if vars[key] == "" && required[key] {
+				failed = true
+				logger.Error(fmt.Sprintf("`%s` is required but empty on %s", key, envFile))
+			}
+		}
+		if scan {
+			for _, key := range sortedKeys(scanned) {
+				_, onEnv := vars[key]
+				_, onExample := example[key]
+				if !onEnv && !onExample {
+					failed = true
+					logger.Error(fmt.Sprintf("`%s` is required by the project but it's in neither %s nor %s", key, envFile, exampleFile))
+				}
+			}
+		}
+
+		if failed {
+			os.Exit(1)
+		}
+		logger.Info(fmt.Sprintf("%s is up to date with %s", envFile, exampleFile))
+	},
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// requiredExampleVars returns the variables of the example file that are required, the ones with an example value
+// or marked with a "# required" comment on the same line or the one above
+func requiredExampleVars(path string, example map[string]string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	required := map[string]bool{}
+	for key, value := range example {
+		if value != "" {
+			required[key] = true
+		}
+	}
+	marked := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if comment, ok := strings.CutPrefix(line, "#"); ok {
+			marked = isRequiredMarker(comment)
+			continue
+		}
+		key, rest, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if ok && key != "" {
+			_, comment, _ := strings.Cut(rest, " #")
+			if marked || isRequiredMarker(comment) {
+				required[key] = true
+			}
+		}
+		marked = false
+	}
+	return required, nil
+}
+
+func isRequiredMarker(comment string) bool {
+	return strings.EqualFold(strings.TrimSpace(comment), "required")
+}
+
+var requiredEnvFuncs = map[string]bool{
+	"RequiredStringEnvVar": true,
+	"RequiredIntEnvVar":    true,
+}
+
+// scanRequiredEnvVars finds the variables required by the go files of the project,
+// either through the env package functions or through `env:"NAME,required"` struct tags
+func scanRequiredEnvVars(root string) (map[string]bool, error) {
+	required := map[string]bool{}
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+		envName := envImportName(file)
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.CallExpr:
+				if envName == "" || len(node.Args) == 0 {
+					return true
+				}
+				selector, ok := node.Fun.(*ast.SelectorExpr)
+				if !ok || !requiredEnvFuncs[selector.Sel.Name] {
+					return true
+				}
+				pkg, ok := selector.X.(*ast.Ident)
+				if !ok || pkg.Name != envName {
+					return true
+				}
+				if key, ok := stringLiteral(node.Args[0]); ok {
+					required[key] = true
+				}
+			case *ast.Field:
+				if node.Tag == nil {
+					return true
+				}
+				tag, ok := stringLiteral(node.Tag)
+				if !ok {
+					return true
+				}
+				key, options, _ := strings.Cut(reflect.StructTag(tag).Get("env"), ",")
+				if key != "" && options == "required" {
+					required[key] = true
+				}
+			}
+			return true
+		})
+		return nil
+	})
+	return required, err
+}
+
+// envImportName returns the name the otter env package is imported with on the file, or empty if it isn't
+func envImportName(file *ast.File) string {
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		if path != envPackagePath {
+			continue
+		}
+		if spec.Name != nil {
+			return spec.Name.Name
+		}
+		return "env"
+	}
+	return ""
+}
+
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
@@ -142,15 +142,12 @@ var migrateUpCmd = &cobra.Command{
 			os.Exit(1)
 
 		}
-		err := os.RemoveAll("./.otter")
+		// only the migration runner is recreated, the .otter directory also holds the dev mail outbox
+		err := os.RemoveAll("./.otter/migrate")
 		if err != nil {
 			fatal(logger, err)
 		}
-		err = os.Mkdir("./.otter", 0755)
-		if err != nil {
-			fatal(logger, err)
-		}
-		err = os.Mkdir("./.otter/migrate", 0755)
+		err = os.MkdirAll("./.otter/migrate", 0755)
 		if err != nil {
 			fatal(logger, err)
 		}
@@ -198,6 +195,12 @@ import (
 )
 
 func main() {
+	err := env.Init()
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+
 	dbUser := env.RequiredStringEnvVar("DB_USER")
 	dbName := env.RequiredStringEnvVar("DB_NAME")
 	dbPassword := env.RequiredStringEnvVar("DB_PASSWORD")
@@ -1,7 +1,7 @@
 package main
 
 import (
-	"github.com/joho/godotenv"
+	"github.com/martinmunillas/otter/env"
 	"github.com/spf13/cobra"
 )
 
@@ -12,10 +12,13 @@ var cmd = &cobra.Command{
 }
 
 func main() {
-	_ = godotenv.Load()
+	if err := env.Init(); err != nil {
+		panic(err)
+	}
 	cmd.AddCommand(devCmd)
 	cmd.AddCommand(initCmd)
 	cmd.AddCommand(migrateCmd)
+	cmd.AddCommand(envCmd)
 	if err := cmd.Execute(); err != nil {
 		panic(err)
 	}
@@ -0,0 +1,180 @@
+package datatable
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/martinmunillas/otter/i18n"
+	"github.com/martinmunillas/otter/pagination"
+)
+
+type Direction string
+
+const (
+	Asc  Direction = "asc"
+	Desc Direction = "desc"
+)
+
+// Column defines how a column of the table is rendered
+type Column[T any] struct {
+	// Key identifies the column on the sort and filter query params
+	Key string
+	// Header is the translation key of the column header
+	Header     string
+	Cell       func(item T) templ.Component
+	Sortable   bool
+	Filterable bool
+}
+
+// Table is a list of typed columns, ParseState reads its state from a request and Render renders a page of items
+type Table[T any] struct {
+	// ID of the table element, it's the target of the sort, filter and page requests
+	ID      string
+	Columns []Column[T]
+}
+
+// State is the sort, filter and pagination state of a table, only holding allowed columns
+type State struct {
+	SortBy     string
+	Direction  Direction
+	Filters    map[string]string
+	Pagination pagination.Params
+	url        *url.URL
+}
+
+// ParseState reads the `sort`, `dir` and `filter.{key}` query params of the request, ignoring the columns
+// that aren't sortable or filterable so the state can be safely used to build queries
+func (t Table[T]) ParseState(r *http.Request) State {
+	query := r.URL.Query()
+	state := State{
+		Direction:  Asc,
+		Filters:    map[string]string{},
+		Pagination: pagination.FromRequest(r),
+		url:        r.URL,
+	}
+	sortBy := query.Get("sort")
+	for _, column := range t.Columns {
+		if column.Sortable && column.Key == sortBy {
+			state.SortBy = sortBy
+		}
+		if value := strings.TrimSpace(query.Get("filter." + column.Key)); column.Filterable && value != "" {
+			state.Filters[column.Key] = value
+		}
+	}
+	if Direction(query.Get("dir")) == Desc {
+		state.Direction = Desc
+	}
+	return state
+}
+
+// OrderBy returns the ORDER BY clause content for the sorted column, mapping the column keys to the given expressions.
+// The fallback is returned when the table isn't sorted or the column isn't in expressions
+func (s State) OrderBy(expressions map[string]string, fallback string) string {
+	expression, ok := expressions[s.SortBy]
+	if !ok {
+		return fallback
+	}
+	if s.Direction == Desc {
+		return expression + " DESC"
+	}
+	return expression + " ASC"
+}
+
+// sortHref returns the url sorting by the column, toggling the direction when it's already sorted by it
+func (s State) sortHref(ctx context.Context, key string) string {
+	query := url.Values{}
+	path := ""
+	if s.url != nil {
+		query = s.url.Query()
+		path = s.url.Path
+	}
+	direction := Asc
+	if s.SortBy == key && s.Direction == Asc {
+		direction = Desc
+	}
+	query.Set("sort", key)
+	query.Set("dir", string(direction))
+	query.Del("page")
+	query.Del("cursor")
+	return i18n.LocalizedHref(ctx, path+"?"+query.Encode())
+}
+
+func (s State) path(ctx context.Context) string {
+	if s.url == nil {
+		return ""
+	}
+	return i18n.LocalizedHref(ctx, s.url.Path)
+}
+
+func (s State) headerAttributes(header HeaderProps) templ.Attributes {
+	if !header.Sortable {
+		return templ.Attributes{}
+	}
+	ariaSort := "none"
+	if s.SortBy == header.Key {
+		ariaSort = "ascending"
+		if s.Direction == Desc {
+			ariaSort = "descending"
+		}
+	}
+	return templ.Attributes{"aria-sort": ariaSort}
+}
+
+// HeaderProps is the non generic version of a column used to render the header
+type HeaderProps struct {
+	Key        string
+	Header     string
+	Sortable   bool
+	Filterable bool
+}
+
+type Props struct {
+	ID      string
+	Headers []HeaderProps
+	Rows    [][]templ.Component
+	State   State
+	Info    pagination.Info
+}
+
+// Render renders the page of items with the table columns
+func (t Table[T]) Render(page pagination.Page[T], state State) templ.Component {
+	props := Props{
+		ID:      t.ID,
+		Headers: make([]HeaderProps, len(t.Columns)),
+		Rows:    make([][]templ.Component, len(page.Items)),
+		State:   state,
+		Info:    page.Info,
+	}
+	for i, column := range t.Columns {
+		props.Headers[i] = HeaderProps{
+			Key:        column.Key,
+			Header:     column.Header,
+			Sortable:   column.Sortable,
+			Filterable: column.Filterable,
+		}
+	}
+	for i, item := range page.Items {
+		props.Rows[i] = make([]templ.Component, len(t.Columns))
+		for j, column := range t.Columns {
+			props.Rows[i][j] = column.Cell(item)
+		}
+	}
+	return DataTable(props)
+}
+
+func (p Props) hasFilters() bool {
+	for _, header := range p.Headers {
+		if header.Filterable {
+			return true
+		}
+	}
+	return false
+}
+
+func selector(id string) string {
+	return fmt.Sprintf("#%s", id)
+}
@@ -0,0 +1,47 @@
+package datatable
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	Name  string
+	Email string
+}
+
+var users = Table[user]{
+	ID: "users",
+	Columns: []Column[user]{
+		{Key: "name", Header: "users.name", Sortable: true, Filterable: true},
+		{Key: "email", Header: "users.email"},
+	},
+}
+
+func TestParseState(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		sortBy  string
+		dir     Direction
+		filters map[string]string
+		orderBy string
+	}{
+		{name: "empty", target: "/users", dir: Asc, filters: map[string]string{}, orderBy: "id"},
+		{name: "sorted", target: "/users?sort=name&dir=desc", sortBy: "name", dir: Desc, filters: map[string]string{}, orderBy: "u.name DESC"},
+		{name: "not sortable", target: "/users?sort=email", dir: Asc, filters: map[string]string{}, orderBy: "id"},
+		{name: "injection", target: "/users?sort=name;drop%20table%20users&dir=up", dir: Asc, filters: map[string]string{}, orderBy: "id"},
+		{name: "filters", target: "/users?filter.name=otter&filter.email=a", dir: Asc, filters: map[string]string{"name": "otter"}, orderBy: "id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := users.ParseState(httptest.NewRequest("GET", tt.target, nil))
+			assert.Equal(t, tt.sortBy, state.SortBy)
+			assert.Equal(t, tt.dir, state.Direction)
+			assert.Equal(t, tt.filters, state.Filters)
+			assert.Equal(t, tt.orderBy, state.OrderBy(map[string]string{"name": "u.name"}, "id"))
+		})
+	}
+}
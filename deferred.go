@@ -0,0 +1,153 @@
+package otter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"sync"
+
+	"github.com/a-h/templ"
+	"github.com/martinmunillas/otter/i18n"
+	"github.com/martinmunillas/otter/log"
+)
+
+type deferredKeyType string
+
+var deferredKey deferredKeyType = "deferred"
+
+type deferredItem struct {
+	id        string
+	component templ.Component
+	done      chan struct{}
+	written   bool
+}
+
+// DeferredStream collects the Deferred components rendered in a response so their content can be streamed
+// once resolved
+type DeferredStream struct {
+	mu     sync.Mutex
+	items  []*deferredItem
+	notify chan struct{}
+}
+
+// WithDeferredStream returns a copy of the context where Deferred components render their placeholder
+// and resolve their content in the background, to be written later by DeferredStream.Write
+func WithDeferredStream(ctx context.Context) (context.Context, *DeferredStream) {
+	stream := &DeferredStream{
+		notify: make(chan struct{}, 1),
+	}
+	return context.WithValue(ctx, deferredKey, stream), stream
+}
+
+func (s *DeferredStream) add(ctx context.Context, resolve func(ctx context.Context) templ.Component) string {
+	s.mu.Lock()
+	item := &deferredItem{
+		id:   fmt.Sprintf("otter-deferred-%d", len(s.items)+1),
+		done: make(chan struct{}),
+	}
+	s.items = append(s.items, item)
+	s.mu.Unlock()
+
+	go func() {
+		component := resolveDeferred(ctx, resolve)
+		s.mu.Lock()
+		item.component = component
+		s.mu.Unlock()
+		close(item.done)
+		select {
+		case s.notify <- struct{}{}:
+		default:
+		}
+	}()
+	return item.id
+}
+
+// resolveDeferred runs the resolve function, a panic is logged and rendered as an error alert
+// as it may run in its own goroutine, where it would take the whole server down
+func resolveDeferred(ctx context.Context, resolve func(ctx context.Context) templ.Component) (component templ.Component) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+		log.Default().Error(fmt.Sprintf("deferred component panicked: %v\n%s", recovered, debug.Stack()))
+		component = ErrorAlert(errors.New(i18n.TranslationOr(ctx, "otter.deferred.error", "Something went wrong")))
+	}()
+	return resolve(ctx)
+}
+
+// Len returns the amount of Deferred components rendered with the stream
+func (s *DeferredStream) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.items)
+}
+
+// next returns a resolved item that hasn't been written yet, and whether there are items left to write
+func (s *DeferredStream) next() (*deferredItem, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	left := false
+	for _, item := range s.items {
+		if item.written {
+			continue
+		}
+		select {
+		case <-item.done:
+			item.written = true
+			return item, true
+		default:
+			left = true
+		}
+	}
+	return nil, left
+}
+
+// Write renders the content of the Deferred components in the order they resolve, calling flush after each one
+// so the client receives them as soon as possible
+func (s *DeferredStream) Write(ctx context.Context, w io.Writer, flush func() error) error {
+	// shared by all the chunks so the swap script is only written once
+	ctx = templ.InitializeContext(ctx)
+	for {
+		item, left := s.next()
+		if !left {
+			return nil
+		}
+		if item == nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-s.notify:
+			}
+			continue
+		}
+		err := deferredContent(item.id, item.component).Render(ctx, w)
+		if err != nil {
+			return err
+		}
+		err = flush()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Deferred renders the placeholder right away and replaces it with the resolved component once it's ready,
+// streamed at the end of the same response. The resolve function runs in the background so it can await slow
+// queries without delaying the rest of the page. When the response doesn't support streaming it's resolved in place
+func Deferred(placeholder templ.Component, resolve func(ctx context.Context) templ.Component) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		stream, ok := ctx.Value(deferredKey).(*DeferredStream)
+		if !ok {
+			component := resolveDeferred(ctx, resolve)
+			if component == nil {
+				return nil
+			}
+			return component.Render(ctx, w)
+		}
+		id := stream.add(ctx, resolve)
+		return deferredPlaceholder(id, placeholder).Render(ctx, w)
+	})
+}
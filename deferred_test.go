@@ -0,0 +1,111 @@
+package otter
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/martinmunillas/otter/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func text(value string) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, value)
+		return err
+	})
+}
+
+func TestDeferredWithoutStream(t *testing.T) {
+	buf := &bytes.Buffer{}
+	component := Deferred(text("loading"), func(ctx context.Context) templ.Component {
+		return text("resolved")
+	})
+
+	err := component.Render(context.Background(), buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "resolved", buf.String())
+}
+
+func TestDeferredStream(t *testing.T) {
+	ctx, stream := WithDeferredStream(context.Background())
+	release := make(chan struct{})
+	slow := Deferred(text("loading slow"), func(ctx context.Context) templ.Component {
+		<-release
+		return text("slow content")
+	})
+	fast := Deferred(text("loading fast"), func(ctx context.Context) templ.Component {
+		return text("fast content")
+	})
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, slow.Render(ctx, buf))
+	assert.NoError(t, fast.Render(ctx, buf))
+	assert.Equal(t, 2, stream.Len())
+	assert.Contains(t, buf.String(), `id="otter-deferred-1"`)
+	assert.Contains(t, buf.String(), "loading slow")
+	assert.Contains(t, buf.String(), `id="otter-deferred-2"`)
+	assert.Contains(t, buf.String(), "loading fast")
+	assert.NotContains(t, buf.String(), "content")
+
+	buf.Reset()
+	flushed := []string{}
+	err := stream.Write(ctx, buf, func() error {
+		flushed = append(flushed, buf.String())
+		// the slow component only resolves once the fast one reached the client
+		if len(flushed) == 1 {
+			close(release)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	assert.Len(t, flushed, 2)
+	assert.Contains(t, flushed[0], `id="otter-deferred-2-content"`)
+	assert.Contains(t, flushed[0], "fast content")
+	assert.NotContains(t, flushed[0], "slow content")
+	assert.Contains(t, flushed[1], `id="otter-deferred-1-content"`)
+	assert.Contains(t, flushed[1], "slow content")
+	assert.Less(t, strings.Index(flushed[1], "fast content"), strings.Index(flushed[1], "slow content"))
+}
+
+func TestDeferredStreamCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx, stream := WithDeferredStream(ctx)
+	release := make(chan struct{})
+	defer close(release)
+	component := Deferred(nil, func(ctx context.Context) templ.Component {
+		<-release
+		return text("content")
+	})
+	assert.NoError(t, component.Render(ctx, io.Discard))
+
+	cancel()
+	err := stream.Write(ctx, io.Discard, func() error { return nil })
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDeferredPanic(t *testing.T) {
+	previous := log.Default()
+	log.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	t.Cleanup(func() {
+		log.SetDefault(previous)
+	})
+
+	ctx, stream := WithDeferredStream(context.Background())
+	component := Deferred(text("loading"), func(ctx context.Context) templ.Component {
+		panic("query failed")
+	})
+	assert.NoError(t, component.Render(ctx, io.Discard))
+
+	buf := &bytes.Buffer{}
+	err := stream.Write(ctx, buf, func() error { return nil })
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), `id="otter-deferred-1-content"`)
+	assert.Contains(t, buf.String(), "Something went wrong")
+	assert.NotContains(t, buf.String(), "query failed")
+}
@@ -0,0 +1,248 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/martinmunillas/otter/i18n"
+)
+
+type Message struct {
+	// From overrides the sender of the mailer
+	From    string
+	To      []string
+	Cc      []string
+	Bcc     []string
+	ReplyTo string
+	// Subject is translated in the recipient locale, subjects without translation are sent as they are
+	Subject string
+	// Locale of the recipient, the components are rendered and the subject translated in it.
+	// The default locale is used when empty
+	Locale  string
+	HTML    templ.Component
+	Text    templ.Component
+	Headers map[string]string
+}
+
+// Email is a rendered message ready to be delivered by a transport
+type Email struct {
+	ID      string
+	Date    time.Time
+	From    string
+	To      []string
+	Cc      []string
+	Bcc     []string
+	ReplyTo string
+	Subject string
+	HTML    string
+	Text    string
+	Headers map[string]string
+}
+
+// Recipients returns every address the email has to be delivered to
+func (e Email) Recipients() []string {
+	recipients := make([]string, 0, len(e.To)+len(e.Cc)+len(e.Bcc))
+	recipients = append(recipients, e.To...)
+	recipients = append(recipients, e.Cc...)
+	recipients = append(recipients, e.Bcc...)
+	return recipients
+}
+
+type Mailer struct {
+	transport Transport
+	from      string
+}
+
+func NewMailer(transport Transport, from string) *Mailer {
+	return &Mailer{
+		transport: transport,
+		from:      from,
+	}
+}
+
+// Render renders the message components and subject in the recipient locale
+func (m *Mailer) Render(ctx context.Context, message Message) (Email, error) {
+	if message.Locale != "" {
+		ctx = i18n.WithLocale(ctx, message.Locale)
+	}
+	if message.HTML == nil && message.Text == nil {
+		return Email{}, errors.New("invalid email: missing both html and text bodies")
+	}
+	if len(message.To)+len(message.Cc)+len(message.Bcc) == 0 {
+		return Email{}, errors.New("invalid email: missing recipients")
+	}
+
+	email := Email{
+		ID:      newID(),
+		Date:    time.Now(),
+		From:    m.from,
+		To:      message.To,
+		Cc:      message.Cc,
+		Bcc:     message.Bcc,
+		ReplyTo: message.ReplyTo,
+		Subject: i18n.Translation(ctx, message.Subject),
+		Headers: message.Headers,
+	}
+	if message.From != "" {
+		email.From = message.From
+	}
+	err := email.validateHeaders()
+	if err != nil {
+		return Email{}, err
+	}
+
+	if message.HTML != nil {
+		body, err := templ.ToGoHTML(ctx, message.HTML)
+		if err != nil {
+			return Email{}, fmt.Errorf("error rendering html body: %w", err)
+		}
+		email.HTML = string(body)
+	}
+	if message.Text != nil {
+		text, err := templ.ToGoHTML(ctx, message.Text)
+		if err != nil {
+			return Email{}, fmt.Errorf("error rendering text body: %w", err)
+		}
+		email.Text = html.UnescapeString(string(text))
+	}
+	return email, nil
+}
+
+// Send renders the message and delivers it through the transport
+func (m *Mailer) Send(ctx context.Context, message Message) error {
+	email, err := m.Render(ctx, message)
+	if err != nil {
+		return err
+	}
+	return m.transport.Send(ctx, email)
+}
+
+// validateHeaders rejects the header values with line breaks, as they are written verbatim
+// and could add headers or recipients to the email
+func (e Email) validateHeaders() error {
+	values := map[string][]string{
+		"From":     {e.From},
+		"To":       e.To,
+		"Cc":       e.Cc,
+		"Bcc":      e.Bcc,
+		"Reply-To": {e.ReplyTo},
+	}
+	for key, value := range e.Headers {
+		if strings.ContainsAny(key, "\r\n:") {
+			return fmt.Errorf("invalid email: header name %q is not valid", key)
+		}
+		values[key] = append(values[key], value)
+	}
+	for key, list := range values {
+		for _, value := range list {
+			if strings.ContainsAny(value, "\r\n") {
+				return fmt.Errorf("invalid email: %s header contains a line break", key)
+			}
+		}
+	}
+	return nil
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Bytes encodes the email in the internet message format, with a multipart/alternative body when it has both
+// html and text versions
+func (e Email) Bytes() ([]byte, error) {
+	err := e.validateHeaders()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	header := textproto.MIMEHeader{}
+	header.Set("From", e.From)
+	if len(e.To) > 0 {
+		header.Set("To", strings.Join(e.To, ", "))
+	}
+	if len(e.Cc) > 0 {
+		header.Set("Cc", strings.Join(e.Cc, ", "))
+	}
+	if e.ReplyTo != "" {
+		header.Set("Reply-To", e.ReplyTo)
+	}
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", e.Subject))
+	header.Set("Date", e.Date.Format(time.RFC1123Z))
+	header.Set("Message-ID", fmt.Sprintf("<%s@otter>", e.ID))
+	header.Set("MIME-Version", "1.0")
+	for key, value := range e.Headers {
+		header.Set(key, value)
+	}
+
+	if e.HTML == "" || e.Text == "" {
+		contentType := "text/plain; charset=utf-8"
+		body := e.Text
+		if e.HTML != "" {
+			contentType = "text/html; charset=utf-8"
+			body = e.HTML
+		}
+		header.Set("Content-Type", contentType)
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		writeHeader(&buf, header)
+		err = writeQuotedPrintable(&buf, body)
+		return buf.Bytes(), err
+	}
+
+	body := multipart.NewWriter(&buf)
+	header.Set("Content-Type", fmt.Sprintf("multipart/alternative; boundary=%s", body.Boundary()))
+	writeHeader(&buf, header)
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{contentType: "text/plain; charset=utf-8", content: e.Text},
+		{contentType: "text/html; charset=utf-8", content: e.HTML},
+	} {
+		w, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		err = writeQuotedPrintable(w, part.content)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = body.Close()
+	return buf.Bytes(), err
+}
+
+func writeHeader(buf *bytes.Buffer, header textproto.MIMEHeader) {
+	for key, values := range header {
+		for _, value := range values {
+			fmt.Fprintf(buf, "%s: %s\r\n", key, value)
+		}
+	}
+	buf.WriteString("\r\n")
+}
+
+func writeQuotedPrintable(w io.Writer, content string) error {
+	qp := quotedprintable.NewWriter(w)
+	_, err := io.WriteString(qp, content)
+	if err != nil {
+		return err
+	}
+	return qp.Close()
+}
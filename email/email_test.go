@@ -0,0 +1,122 @@
+package email
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/stretchr/testify/assert"
+)
+
+func text(value string) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, value)
+		return err
+	})
+}
+
+func testEmail() Email {
+	return Email{
+		ID:      "1",
+		Date:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		From:    "otter@example.com",
+		To:      []string{"a@example.com", "b@example.com"},
+		Cc:      []string{"c@example.com"},
+		Bcc:     []string{"d@example.com"},
+		ReplyTo: "support@example.com",
+		Subject: "Hola señor",
+		Text:    "Hello",
+		Headers: map[string]string{"X-Campaign": "welcome"},
+	}
+}
+
+func TestBytes(t *testing.T) {
+	data, err := testEmail().Bytes()
+	assert.NoError(t, err)
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(data)))
+	assert.NoError(t, err)
+	assert.Equal(t, "otter@example.com", msg.Header.Get("From"))
+	assert.Equal(t, "a@example.com, b@example.com", msg.Header.Get("To"))
+	assert.Equal(t, "c@example.com", msg.Header.Get("Cc"))
+	assert.Empty(t, msg.Header.Get("Bcc"))
+	assert.Equal(t, "support@example.com", msg.Header.Get("Reply-To"))
+	assert.Equal(t, "welcome", msg.Header.Get("X-Campaign"))
+	assert.Equal(t, "<1@otter>", msg.Header.Get("Message-Id"))
+	assert.Equal(t, "text/plain; charset=utf-8", msg.Header.Get("Content-Type"))
+	subject, err := (&mime.WordDecoder{}).DecodeHeader(msg.Header.Get("Subject"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Hola señor", subject)
+	body, err := io.ReadAll(msg.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello", string(body))
+}
+
+func TestBytesMultipart(t *testing.T) {
+	email := testEmail()
+	email.HTML = "<p>Hello</p>"
+	data, err := email.Bytes()
+	assert.NoError(t, err)
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(data)))
+	assert.NoError(t, err)
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	assert.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	contents := map[string]string{}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		content, err := io.ReadAll(part)
+		assert.NoError(t, err)
+		contents[part.Header.Get("Content-Type")] = string(content)
+	}
+	assert.Equal(t, map[string]string{
+		"text/plain; charset=utf-8": "Hello",
+		"text/html; charset=utf-8":  "<p>Hello</p>",
+	}, contents)
+}
+
+func TestHeaderInjection(t *testing.T) {
+	testcases := []struct {
+		name   string
+		update func(email *Email)
+	}{
+		{name: "from", update: func(email *Email) { email.From = "otter@example.com\r\nBcc: victim@example.com" }},
+		{name: "to", update: func(email *Email) { email.To = []string{"a@example.com\nBcc: victim@example.com"} }},
+		{name: "cc", update: func(email *Email) { email.Cc = []string{"c@example.com\r"} }},
+		{name: "reply to", update: func(email *Email) { email.ReplyTo = "support@example.com\r\nX-Injected: true" }},
+		{name: "header value", update: func(email *Email) { email.Headers = map[string]string{"X-Campaign": "a\r\nBcc: victim@example.com"} }},
+		{name: "header name", update: func(email *Email) { email.Headers = map[string]string{"X-Campaign\r\nBcc": "victim@example.com"} }},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			email := testEmail()
+			testcase.update(&email)
+			_, err := email.Bytes()
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("render", func(t *testing.T) {
+		transport := NewMemoryTransport()
+		mailer := NewMailer(transport, "otter@example.com")
+		err := mailer.Send(context.Background(), Message{
+			To:   []string{"a@example.com\r\nBcc: victim@example.com"},
+			Text: text("Hello"),
+		})
+		assert.Error(t, err)
+		assert.Empty(t, transport.Emails())
+	})
+}
@@ -0,0 +1,153 @@
+package email
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/http"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+type outboxEmail struct {
+	ID      string
+	Date    time.Time
+	From    string
+	To      string
+	Cc      string
+	Subject string
+	HTML    string
+	Text    string
+}
+
+func readOutbox(dir string) ([]outboxEmail, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, "new"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	emails := make([]outboxEmail, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		email, err := readOutboxEmail(dir, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		emails = append(emails, email)
+	}
+	sort.Slice(emails, func(i, j int) bool {
+		return emails[i].Date.After(emails[j].Date)
+	})
+	return emails, nil
+}
+
+func readOutboxEmail(dir string, id string) (outboxEmail, error) {
+	if id != filepath.Base(id) {
+		return outboxEmail{}, os.ErrNotExist
+	}
+	f, err := os.Open(filepath.Join(dir, "new", id))
+	if err != nil {
+		return outboxEmail{}, err
+	}
+	defer f.Close()
+
+	msg, err := mail.ReadMessage(f)
+	if err != nil {
+		return outboxEmail{}, fmt.Errorf("error reading email %s: %w", id, err)
+	}
+	decoder := mime.WordDecoder{}
+	subject, err := decoder.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		subject = msg.Header.Get("Subject")
+	}
+	date, _ := msg.Header.Date()
+	email := outboxEmail{
+		ID:      id,
+		Date:    date,
+		From:    msg.Header.Get("From"),
+		To:      msg.Header.Get("To"),
+		Cc:      msg.Header.Get("Cc"),
+		Subject: subject,
+	}
+	err = readOutboxBody(&email, msg.Header.Get("Content-Type"), msg.Body)
+	return email, err
+}
+
+func readOutboxBody(email *outboxEmail, contentType string, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		// single part bodies aren't decoded by the multipart reader
+		content, err := io.ReadAll(quotedprintable.NewReader(body))
+		if err != nil {
+			return err
+		}
+		if mediaType == "text/html" {
+			email.HTML = string(content)
+		} else {
+			email.Text = string(content)
+		}
+		return nil
+	}
+	reader := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return err
+		}
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if partType == "text/html" {
+			email.HTML = string(content)
+		} else {
+			email.Text = string(content)
+		}
+	}
+}
+
+// OutboxHandler serves a viewer for the emails written by a FileTransport into the given directory
+func OutboxHandler(dir string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := r.PathValue("id")
+		if id == "" {
+			emails, err := readOutbox(dir)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			_ = outboxList(emails).Render(ctx, w)
+			return
+		}
+
+		email, err := readOutboxEmail(dir, id)
+		if errors.Is(err, os.ErrNotExist) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_ = outboxDetail(email).Render(ctx, w)
+	})
+}
@@ -0,0 +1,74 @@
+package email
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutbox(t *testing.T) {
+	dir := t.TempDir()
+	transport := FileTransport{Dir: dir}
+
+	first := testEmail()
+	second := testEmail()
+	second.ID = "2"
+	second.Date = first.Date.Add(time.Hour)
+	second.Subject = "Second"
+	second.HTML = "<p>Hello</p>"
+	assert.NoError(t, transport.Send(context.Background(), first))
+	assert.NoError(t, transport.Send(context.Background(), second))
+
+	emails, err := readOutbox(dir)
+	assert.NoError(t, err)
+	assert.Len(t, emails, 2)
+
+	// the newest email is listed first
+	assert.Equal(t, "Second", emails[0].Subject)
+	assert.Equal(t, "Hello", emails[0].Text)
+	assert.Equal(t, "<p>Hello</p>", emails[0].HTML)
+	assert.True(t, second.Date.Equal(emails[0].Date))
+
+	assert.Equal(t, "Hola señor", emails[1].Subject)
+	assert.Equal(t, "otter@example.com", emails[1].From)
+	assert.Equal(t, "a@example.com, b@example.com", emails[1].To)
+	assert.Equal(t, "c@example.com", emails[1].Cc)
+	assert.Equal(t, "Hello", emails[1].Text)
+	assert.Empty(t, emails[1].HTML)
+
+	email, err := readOutboxEmail(dir, emails[1].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, emails[1], email)
+
+	_, err = readOutboxEmail(dir, "../"+emails[1].ID)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestOutboxEmpty(t *testing.T) {
+	emails, err := readOutbox(t.TempDir())
+	assert.NoError(t, err)
+	assert.Empty(t, emails)
+}
+
+func TestOutboxHandler(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, FileTransport{Dir: dir}.Send(context.Background(), testEmail()))
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /mail", OutboxHandler(dir))
+	mux.Handle("GET /mail/{id}", OutboxHandler(dir))
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/mail", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Hola señor")
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/mail/missing", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
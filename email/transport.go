@@ -0,0 +1,103 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type Transport interface {
+	Send(ctx context.Context, email Email) error
+}
+
+type SMTPTransport struct {
+	Host     string
+	Port     int64
+	Username string
+	Password string
+}
+
+func (s SMTPTransport) Send(ctx context.Context, email Email) error {
+	data, err := email.Bytes()
+	if err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+	return smtp.SendMail(fmt.Sprintf("%s:%d", s.Host, s.Port), auth, email.From, email.Recipients(), data)
+}
+
+// FileTransport writes every email into a maildir directory instead of delivering it
+type FileTransport struct {
+	Dir string
+}
+
+func (f FileTransport) Send(ctx context.Context, email Email) error {
+	data, err := email.Bytes()
+	if err != nil {
+		return err
+	}
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		err = os.MkdirAll(filepath.Join(f.Dir, sub), 0755)
+		if err != nil {
+			return err
+		}
+	}
+	name := fmt.Sprintf("%d.%s.otter", email.Date.UnixNano(), email.ID)
+	tmp := filepath.Join(f.Dir, "tmp", name)
+	err = os.WriteFile(tmp, data, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(f.Dir, "new", name))
+}
+
+// DevOutboxDir is where the emails are stored while running `otter dev`
+const DevOutboxDir = ".otter/mail"
+
+// DevTransport writes the emails into the dev outbox when running under `otter dev`, so they can be read at
+// /_otter/mail, otherwise it returns the given transport
+func DevTransport(transport Transport) Transport {
+	if os.Getenv("OTTER_DEV_SERVER") == "true" {
+		return FileTransport{Dir: DevOutboxDir}
+	}
+	return transport
+}
+
+// MemoryTransport keeps the sent emails in memory, meant to be used in tests
+type MemoryTransport struct {
+	mu     sync.Mutex
+	emails []Email
+}
+
+func NewMemoryTransport() *MemoryTransport {
+	return &MemoryTransport{}
+}
+
+func (m *MemoryTransport) Send(ctx context.Context, email Email) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.emails = append(m.emails, email)
+	return nil
+}
+
+// Emails returns a copy of the sent emails in the order they were sent
+func (m *MemoryTransport) Emails() []Email {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	emails := make([]Email, len(m.emails))
+	copy(emails, m.emails)
+	return emails
+}
+
+// Reset forgets the sent emails
+func (m *MemoryTransport) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.emails = nil
+}
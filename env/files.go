@@ -0,0 +1,64 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/joho/godotenv"
+)
+
+const (
+	Development = "development"
+	Test        = "test"
+	Production  = "production"
+)
+
+// Environment returns the environment set on OTTER_ENV, when it's not set it defaults to "test"
+// while running under `go test` and to "development" otherwise
+func Environment() string {
+	environment := os.Getenv("OTTER_ENV")
+	if environment != "" {
+		return environment
+	}
+	if testing.Testing() {
+		return Test
+	}
+	return Development
+}
+
+// Files returns the env files of the environment ordered from the highest to the lowest precedence
+func Files(environment string) []string {
+	files := []string{fmt.Sprintf(".env.%s.local", environment)}
+	if environment != Test {
+		files = append(files, ".env.local")
+	}
+	files = append(files, fmt.Sprintf(".env.%s", environment))
+	if environment != Test {
+		files = append(files, ".env")
+	}
+	return files
+}
+
+// Init loads the env files of the current environment, missing files are skipped.
+// When a variable is defined in more than one place the first one of this list wins:
+//
+//  1. the variables already set on the process
+//  2. .env.{environment}.local
+//  3. .env.local
+//  4. .env.{environment}
+//  5. .env
+//
+// .env and .env.local hold each developer's personal settings, so they aren't loaded on the test environment
+// to keep tests reproducible, use .env.test and .env.test.local instead.
+func Init() error {
+	for _, file := range Files(Environment()) {
+		// godotenv never overrides variables that are already set, so files are loaded by precedence
+		err := godotenv.Load(file)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("error loading %s: %w", file, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package env
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldError describes why an environment variable couldn't be loaded into a struct field
+type FieldError struct {
+	Field string
+	Key   string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("env variable `%s` (%s): %v", e.Key, e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+var ErrMissing = errors.New("missing required value")
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	urlType             = reflect.TypeOf(url.URL{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Load populates the struct pointed by cfg from the environment variables named on its `env` tags,
+// reporting every missing or invalid variable at once.
+//
+//	type Config struct {
+//		Port        int64         `env:"PORT" default:"8080"`
+//		DatabaseURL *url.URL      `env:"DATABASE_URL,required"`
+//		Timeout     time.Duration `env:"TIMEOUT" default:"5s"`
+//		Origins     []string      `env:"ALLOWED_ORIGINS"`
+//	}
+//
+// When a variable is not set but the same variable with the `_FILE` suffix is, the value is read from the file
+// it points to, as secrets mounted by docker or kubernetes are. Slices are read as comma separated values and
+// nested structs without an `env` tag are loaded recursively.
+func Load(cfg any) error {
+	val := reflect.ValueOf(cfg)
+	if val.Kind() != reflect.Pointer || val.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("invalid env.Load() call: expected a pointer to a struct but got %T", cfg)
+	}
+	return errors.Join(loadStruct(val.Elem(), "")...)
+}
+
+func loadStruct(val reflect.Value, prefix string) []error {
+	var errs []error
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag, hasTag := field.Tag.Lookup("env")
+		if !hasTag {
+			if field.Type.Kind() == reflect.Struct && field.Type != urlType {
+				errs = append(errs, loadStruct(val.Field(i), prefix+field.Name+".")...)
+			}
+			continue
+		}
+
+		key, options, _ := strings.Cut(tag, ",")
+		required := options == "required"
+		value, err := lookup(key)
+		if err != nil {
+			errs = append(errs, &FieldError{Field: prefix + field.Name, Key: key, Err: err})
+			continue
+		}
+		if value == "" {
+			value = field.Tag.Get("default")
+		}
+		if value == "" {
+			if required {
+				errs = append(errs, &FieldError{Field: prefix + field.Name, Key: key, Err: ErrMissing})
+			}
+			continue
+		}
+
+		err = setValue(val.Field(i), value)
+		if err != nil {
+			errs = append(errs, &FieldError{Field: prefix + field.Name, Key: key, Err: err})
+		}
+	}
+	return errs
+}
+
+// lookup reads the variable, falling back to the contents of the file named by the `_FILE` variable
+func lookup(key string) (string, error) {
+	value := os.Getenv(key)
+	if value != "" {
+		return value, nil
+	}
+	path := os.Getenv(key + "_FILE")
+	if path == "" {
+		return "", nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading %s_FILE: %w", key, err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+func setValue(field reflect.Value, value string) error {
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	switch field.Type() {
+	case durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration \"%s\"", value)
+		}
+		field.SetInt(int64(d))
+		return nil
+	case urlType:
+		u, err := url.Parse(value)
+		if err != nil {
+			return fmt.Errorf("invalid url \"%s\"", value)
+		}
+		field.Set(reflect.ValueOf(*u))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.Pointer:
+		ptr := reflect.New(field.Type().Elem())
+		err := setValue(ptr.Elem(), value)
+		if err != nil {
+			return err
+		}
+		field.Set(ptr)
+	case reflect.Slice:
+		parts := strings.Split(value, ",")
+		slice := reflect.MakeSlice(field.Type(), 0, len(parts))
+		for _, part := range parts {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			item := reflect.New(field.Type().Elem()).Elem()
+			err := setValue(item, part)
+			if err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, item)
+		}
+		field.Set(slice)
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool \"%s\"", value)
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid int \"%s\"", value)
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid uint \"%s\"", value)
+		}
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid float \"%s\"", value)
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
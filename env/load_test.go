@@ -0,0 +1,81 @@
+package env
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "secret")
+	assert.NoError(t, os.WriteFile(secret, []byte("s3cr3t\n"), 0600))
+
+	t.Setenv("TEST_NAME", "otter")
+	t.Setenv("TEST_PORT", "8080")
+	t.Setenv("TEST_DEBUG", "true")
+	t.Setenv("TEST_RATIO", "0.75")
+	t.Setenv("TEST_TIMEOUT", "1m30s")
+	t.Setenv("TEST_ORIGINS", "http://a.com, http://b.com")
+	t.Setenv("TEST_URL", "postgres://localhost:5432/db")
+	t.Setenv("TEST_IP", "127.0.0.1")
+	t.Setenv("TEST_PASSWORD_FILE", secret)
+
+	type database struct {
+		URL      *url.URL `env:"TEST_URL,required"`
+		Password string   `env:"TEST_PASSWORD,required"`
+	}
+	cfg := struct {
+		Name     string        `env:"TEST_NAME"`
+		Port     int64         `env:"TEST_PORT"`
+		Debug    bool          `env:"TEST_DEBUG"`
+		Ratio    float64       `env:"TEST_RATIO"`
+		Timeout  time.Duration `env:"TEST_TIMEOUT"`
+		Origins  []string      `env:"TEST_ORIGINS"`
+		IP       net.IP        `env:"TEST_IP"`
+		Workers  uint          `env:"TEST_WORKERS" default:"4"`
+		Optional string        `env:"TEST_OPTIONAL"`
+		Database database
+	}{}
+
+	assert.NoError(t, Load(&cfg))
+	assert.Equal(t, "otter", cfg.Name)
+	assert.Equal(t, int64(8080), cfg.Port)
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, 0.75, cfg.Ratio)
+	assert.Equal(t, 90*time.Second, cfg.Timeout)
+	assert.Equal(t, []string{"http://a.com", "http://b.com"}, cfg.Origins)
+	assert.Equal(t, "127.0.0.1", cfg.IP.String())
+	assert.Equal(t, uint(4), cfg.Workers)
+	assert.Equal(t, "", cfg.Optional)
+	assert.Equal(t, "localhost:5432", cfg.Database.URL.Host)
+	assert.Equal(t, "s3cr3t", cfg.Database.Password)
+}
+
+func TestLoadReportsAllErrors(t *testing.T) {
+	t.Setenv("TEST_PORT", "not a number")
+	t.Setenv("TEST_TIMEOUT", "10")
+
+	cfg := struct {
+		Port    int64         `env:"TEST_PORT"`
+		Timeout time.Duration `env:"TEST_TIMEOUT"`
+		Secret  string        `env:"TEST_MISSING_SECRET,required"`
+	}{}
+
+	err := Load(&cfg)
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrMissing)
+	assert.ErrorContains(t, err, "TEST_PORT")
+	assert.ErrorContains(t, err, "TEST_TIMEOUT")
+	assert.ErrorContains(t, err, "TEST_MISSING_SECRET")
+
+	var fieldErr *FieldError
+	assert.True(t, errors.As(err, &fieldErr))
+
+	assert.Error(t, Load(cfg))
+}
@@ -11,12 +11,12 @@ import templruntime "github.com/a-h/templ/runtime"
 func errorAlert() templ.CSSClass {
 	templ_7745c5c3_CSSBuilder := templruntime.GetBuilder()
 	templ_7745c5c3_CSSBuilder.WriteString(`background:var(--danger);`)
-	templ_7745c5c3_CSSBuilder.WriteString(`color:white;`)
-	templ_7745c5c3_CSSBuilder.WriteString(`border-radius:0.25rem;`)
+	templ_7745c5c3_CSSBuilder.WriteString(`color:var(--danger-foreground, white);`)
+	templ_7745c5c3_CSSBuilder.WriteString(`border-radius:var(--radius, 0.25rem);`)
 	templ_7745c5c3_CSSBuilder.WriteString(`display:flex;`)
 	templ_7745c5c3_CSSBuilder.WriteString(`flex-direction:column;`)
-	templ_7745c5c3_CSSBuilder.WriteString(`gap:0.5rem;`)
-	templ_7745c5c3_CSSBuilder.WriteString(`padding:1rem;`)
+	templ_7745c5c3_CSSBuilder.WriteString(`gap:calc(var(--spacing, 0.25rem) * 2);`)
+	templ_7745c5c3_CSSBuilder.WriteString(`padding:calc(var(--spacing, 0.25rem) * 4);`)
 	templ_7745c5c3_CSSID := templ.CSSID(`errorAlert`, templ_7745c5c3_CSSBuilder.String())
 	return templ.ComponentCSSClass{
 		ID:    templ_7745c5c3_CSSID,
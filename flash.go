@@ -0,0 +1,197 @@
+package otter
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const flashCookieName = "otter-flash"
+
+// flashMaxAge is the amount of seconds a flash survives waiting for the next full page load
+const flashMaxAge = 300
+
+var flashSecret = randomSecret()
+
+func randomSecret() []byte {
+	secret := make([]byte, 32)
+	_, _ = rand.Read(secret)
+	return secret
+}
+
+// SetFlashSecret changes the key used to sign the flash cookie, by default a random key is generated on startup
+// so it should be set when running more than one instance of the server
+func SetFlashSecret(secret []byte) {
+	flashSecret = secret
+}
+
+type flashKeyType string
+
+var flashKey flashKeyType = "flash"
+
+type flashState struct {
+	// waiting are the toasts of the cookie, pending the ones shown by this response when it's a full page load
+	waiting  []Toast
+	pending  []Toast
+	outgoing []Toast
+	// consumed is set once the response shows the pending toasts
+	consumed bool
+}
+
+// flashWriter clears the flashed toasts once the html page that shows them is sent
+type flashWriter struct {
+	http.ResponseWriter
+	state       *flashState
+	wroteHeader bool
+}
+
+func (w *flashWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		isPage := strings.HasPrefix(w.Header().Get("Content-Type"), "text/html")
+		if len(w.state.pending) > 0 && status == http.StatusOK && isPage {
+			w.state.consumed = true
+			_ = w.state.setCookie(w.ResponseWriter)
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *flashWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *flashWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// setCookie stores the toasts that still have to be shown, the waiting ones are dropped once consumed
+func (s *flashState) setCookie(w http.ResponseWriter) error {
+	toasts := s.outgoing
+	if !s.consumed {
+		toasts = append(append([]Toast{}, s.waiting...), s.outgoing...)
+	}
+	if len(toasts) == 0 {
+		setFlashCookie(w, "", -1)
+		return nil
+	}
+	value, err := encodeFlash(toasts)
+	if err != nil {
+		return err
+	}
+	setFlashCookie(w, value, flashMaxAge)
+	return nil
+}
+
+func signFlash(payload string) string {
+	mac := hmac.New(sha256.New, flashSecret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func encodeFlash(toasts []Toast) (string, error) {
+	data, err := json.Marshal(toasts)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + signFlash(payload), nil
+}
+
+func decodeFlash(value string) ([]Toast, error) {
+	payload, signature, ok := strings.Cut(value, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(signFlash(payload))) {
+		return nil, errors.New("invalid flash signature")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, err
+	}
+	var toasts []Toast
+	err = json.Unmarshal(data, &toasts)
+	return toasts, err
+}
+
+// isFullPageLoad reports whether the response to the request is going to render a whole page,
+// and therefore the ToastHandler
+func isFullPageLoad(r *http.Request) bool {
+	if r.Method != http.MethodGet {
+		return false
+	}
+	return r.Header.Get("HX-Request") != "true" ||
+		r.Header.Get("HX-Boosted") == "true" ||
+		r.Header.Get("HX-History-Restore-Request") == "true"
+}
+
+// FlashMiddleware reads the toasts flashed by previous requests and hands them over to the ToastHandler
+// on the next full page load. They are cleared once an html page is sent, so requests that don't render one,
+// like assets or json, don't consume them
+func FlashMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		state := &flashState{}
+		cookie, err := r.Cookie(flashCookieName)
+		if err == nil {
+			toasts, err := decodeFlash(cookie.Value)
+			if err != nil {
+				setFlashCookie(w, "", -1)
+			} else {
+				state.waiting = toasts
+				if isFullPageLoad(r) {
+					state.pending = toasts
+				}
+			}
+		}
+		ctx := context.WithValue(r.Context(), flashKey, state)
+		next.ServeHTTP(&flashWriter{ResponseWriter: w, state: state}, r.WithContext(ctx))
+	})
+}
+
+// setFlashCookie replaces any flash cookie already set on the response
+func setFlashCookie(w http.ResponseWriter, value string, maxAge int) {
+	header := w.Header()
+	cookies := header.Values("Set-Cookie")
+	header.Del("Set-Cookie")
+	for _, c := range cookies {
+		if !strings.HasPrefix(c, flashCookieName+"=") {
+			header.Add("Set-Cookie", c)
+		}
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     flashCookieName,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// AddFlash stores the toasts so they are shown on the next full page load
+func AddFlash(w http.ResponseWriter, r *http.Request, toasts ...Toast) error {
+	state, ok := r.Context().Value(flashKey).(*flashState)
+	if !ok {
+		state = &flashState{}
+	}
+	for _, toast := range toasts {
+		state.outgoing = append(state.outgoing, toast.Translate(r.Context()))
+	}
+	return state.setCookie(w)
+}
+
+// FlashedToasts returns the toasts flashed by previous requests that are pending to be shown
+func FlashedToasts(ctx context.Context) []Toast {
+	state, ok := ctx.Value(flashKey).(*flashState)
+	if !ok {
+		return nil
+	}
+	return state.pending
+}
@@ -0,0 +1,133 @@
+package otter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlashEncoding(t *testing.T) {
+	toasts := []Toast{SuccessToast("Saved"), DangerToast("Failed")}
+	value, err := encodeFlash(toasts)
+	assert.NoError(t, err)
+
+	decoded, err := decodeFlash(value)
+	assert.NoError(t, err)
+	assert.Equal(t, toasts, decoded)
+
+	payload, signature, _ := strings.Cut(value, ".")
+	forged, err := encodeFlash([]Toast{InfoToast("Forged")})
+	assert.NoError(t, err)
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	for name, value := range map[string]string{
+		"unsigned":          payload,
+		"tampered payload":  forgedPayload + "." + signature,
+		"tampered sig":      payload + "." + signature[1:],
+		"invalid base64":    "!!!." + signFlash("!!!"),
+		"invalid json":      "e30." + signFlash("e30"),
+		"empty":             "",
+		"missing signature": payload + ".",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := decodeFlash(value)
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("other secret", func(t *testing.T) {
+		previous := flashSecret
+		SetFlashSecret([]byte("other"))
+		t.Cleanup(func() { SetFlashSecret(previous) })
+		_, err := decodeFlash(value)
+		assert.Error(t, err)
+	})
+}
+
+func TestFlashMiddleware(t *testing.T) {
+	value, err := encodeFlash([]Toast{SuccessToast("Saved")})
+	assert.NoError(t, err)
+
+	serve := func(r *http.Request, handle func(w http.ResponseWriter, r *http.Request)) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		FlashMiddleware(http.HandlerFunc(handle)).ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("full page load", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.AddCookie(&http.Cookie{Name: flashCookieName, Value: value})
+		var toasts []Toast
+		w := serve(r, func(w http.ResponseWriter, r *http.Request) {
+			toasts = FlashedToasts(r.Context())
+		})
+		assert.Equal(t, []Toast{SuccessToast("Saved")}, toasts)
+		// not an html page, so they are still waiting to be shown
+		assert.Empty(t, w.Header().Values("Set-Cookie"))
+	})
+
+	t.Run("consumed by html pages", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.AddCookie(&http.Cookie{Name: flashCookieName, Value: value})
+		w := serve(r, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			assert.NoError(t, ToastHandler().Render(r.Context(), w))
+		})
+		assert.Contains(t, w.Body.String(), `<div class="toast toast-success" role="status"><div><span>Saved</span></div></div>`)
+		assert.Contains(t, w.Header().Get("Set-Cookie"), "Max-Age=0")
+	})
+
+	t.Run("keeps the toasts flashed by the page", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.AddCookie(&http.Cookie{Name: flashCookieName, Value: value})
+		w := serve(r, func(w http.ResponseWriter, r *http.Request) {
+			assert.NoError(t, AddFlash(w, r, DangerToast("Failed")))
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+		})
+		cookies := w.Result().Cookies()
+		assert.Len(t, cookies, 1)
+		toasts, err := decodeFlash(cookies[0].Value)
+		assert.NoError(t, err)
+		assert.Equal(t, []Toast{DangerToast("Failed")}, toasts)
+	})
+
+	t.Run("not consumed by htmx requests", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("HX-Request", "true")
+		r.AddCookie(&http.Cookie{Name: flashCookieName, Value: value})
+		var toasts []Toast
+		w := serve(r, func(w http.ResponseWriter, r *http.Request) {
+			toasts = FlashedToasts(r.Context())
+		})
+		assert.Empty(t, toasts)
+		assert.Empty(t, w.Header().Values("Set-Cookie"))
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.AddCookie(&http.Cookie{Name: flashCookieName, Value: "e30.invalid"})
+		var toasts []Toast
+		w := serve(r, func(w http.ResponseWriter, r *http.Request) {
+			toasts = FlashedToasts(r.Context())
+		})
+		assert.Empty(t, toasts)
+		assert.Contains(t, w.Header().Get("Set-Cookie"), "Max-Age=0")
+	})
+
+	t.Run("adds to waiting toasts", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/", nil)
+		r.AddCookie(&http.Cookie{Name: flashCookieName, Value: value})
+		w := serve(r, func(w http.ResponseWriter, r *http.Request) {
+			assert.NoError(t, AddFlash(w, r, DangerToast("Failed")))
+		})
+		cookies := w.Result().Cookies()
+		assert.Len(t, cookies, 1)
+		toasts, err := decodeFlash(cookies[0].Value)
+		assert.NoError(t, err)
+		assert.Equal(t, []Toast{SuccessToast("Saved"), DangerToast("Failed")}, toasts)
+	})
+}
@@ -0,0 +1,145 @@
+package gallery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+
+	"github.com/a-h/templ"
+	"github.com/martinmunillas/otter"
+	"github.com/martinmunillas/otter/i18n"
+	"github.com/martinmunillas/otter/utils"
+)
+
+type Variant struct {
+	Name      string
+	Component templ.Component
+}
+
+// Story groups the variants of a component, usually one per relevant combination of props
+type Story struct {
+	Name        string
+	Description string
+	Variants    []Variant
+}
+
+var stories = []Story{}
+
+// builtins are the names of the registered stories of the otter components, the app can override them
+var builtins = map[string]bool{}
+
+var builtinsOnce sync.Once
+
+var theme = otter.DefaultTheme
+
+// Register adds the story to the gallery served under /_otter/components while running `otter dev`,
+// replacing the story of the otter component with the same name if any
+func Register(story Story) {
+	for i, s := range stories {
+		if s.Name != story.Name {
+			continue
+		}
+		if !builtins[s.Name] {
+			utils.Throw(fmt.Sprintf("story `%s` is already registered", story.Name))
+		}
+		delete(builtins, s.Name)
+		stories[i] = story
+		return
+	}
+	stories = append(stories, story)
+}
+
+func registerBuiltin(story Story) {
+	if _, ok := findStory(story.Name); ok {
+		return
+	}
+	builtins[story.Name] = true
+	stories = append(stories, story)
+}
+
+// SetTheme changes the theme the stories are rendered with, otter.DefaultTheme by default
+func SetTheme(t otter.Theme) {
+	theme = t
+}
+
+func findStory(name string) (Story, bool) {
+	for _, story := range stories {
+		if story.Name == name {
+			return story, true
+		}
+	}
+	return Story{}, false
+}
+
+func findVariant(story Story, name string) (Variant, bool) {
+	for _, variant := range story.Variants {
+		if variant.Name == name {
+			return variant, true
+		}
+	}
+	return Variant{}, false
+}
+
+// locales returns the locales the variants are rendered in, the default one when the app doesn't add any
+func locales() []string {
+	l := i18n.Locales()
+	if len(l) == 0 {
+		return []string{i18n.FromCtx(context.Background())}
+	}
+	return l
+}
+
+func frameSrc(story Story, variant Variant, locale string, mode otter.ThemeMode) string {
+	query := url.Values{}
+	query.Set("locale", locale)
+	query.Set("theme", string(mode))
+	return fmt.Sprintf("/_otter/components/%s/%s?%s", url.PathEscape(story.Name), url.PathEscape(variant.Name), query.Encode())
+}
+
+func storyHref(story Story) string {
+	return "/_otter/components?story=" + url.QueryEscape(story.Name)
+}
+
+// Handler serves the list of stories and renders each variant in every locale in light and dark theme.
+// Every variant is rendered in its own frame so their scripts and ids don't collide.
+// The stories of the otter components are registered along with it, after the ones of the app
+func Handler() http.Handler {
+	builtinsOnce.Do(registerBuiltins)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		storyName := r.PathValue("story")
+		if storyName == "" {
+			if len(stories) == 0 {
+				_ = galleryPage(stories, Story{}).Render(ctx, w)
+				return
+			}
+			story, ok := findStory(r.URL.Query().Get("story"))
+			if !ok {
+				story = stories[0]
+			}
+			_ = galleryPage(stories, story).Render(ctx, w)
+			return
+		}
+
+		story, ok := findStory(storyName)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		variant, ok := findVariant(story, r.PathValue("variant"))
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		locale := r.URL.Query().Get("locale")
+		if !slices.Contains(locales(), locale) {
+			locale = locales()[0]
+		}
+		ctx = i18n.WithLocale(ctx, locale)
+		ctx = otter.WithThemeMode(ctx, otter.ThemeMode(r.URL.Query().Get("theme")))
+		_ = variantFrame(variant).Render(ctx, w)
+	})
+}
@@ -0,0 +1,41 @@
+package gallery
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/stretchr/testify/assert"
+)
+
+func resetStories(t *testing.T) {
+	reset := func() {
+		stories = []Story{}
+		builtins = map[string]bool{}
+		builtinsOnce = sync.Once{}
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestRegister(t *testing.T) {
+	resetStories(t)
+	assert.Empty(t, stories)
+
+	custom := Story{Name: "Checkbox", Variants: []Variant{{Name: "Custom", Component: templ.NopComponent}}}
+	Register(custom)
+	Handler()
+
+	story, ok := findStory("Checkbox")
+	assert.True(t, ok)
+	assert.Equal(t, custom, story)
+	_, ok = findStory("TextInput")
+	assert.True(t, ok)
+
+	// registered after the gallery is mounted
+	override := Story{Name: "TextInput", Variants: []Variant{{Name: "Custom", Component: templ.NopComponent}}}
+	Register(override)
+	story, _ = findStory("TextInput")
+	assert.Equal(t, override, story)
+	assert.Len(t, stories, len(builtins)+2)
+}
@@ -0,0 +1,57 @@
+package gallery
+
+import (
+	"errors"
+
+	"github.com/a-h/templ"
+	"github.com/martinmunillas/otter"
+	"github.com/martinmunillas/otter/i18n"
+)
+
+// registerBuiltins adds the stories of the otter components, skipping the ones the app already registered
+func registerBuiltins() {
+	value := "otter@example.com"
+	registerBuiltin(Story{
+		Name: "TextInput",
+		Variants: []Variant{
+			{Name: "Default", Component: otter.TextInput(otter.InputProps{Name: "name", Label: "Name", Placeholder: "Otter"})},
+			{Name: "Required with help", Component: otter.TextInput(otter.InputProps{Name: "email", Label: "Email", Value: &value, Required: true, Help: "We never share it"})},
+			{Name: "With error", Component: otter.TextInput(otter.InputProps{Name: "username", Label: "Username", Error: errors.New("Username already taken")})},
+			{Name: "Read only", Component: otter.TextInput(otter.InputProps{Name: "id", Label: "ID", Value: &value, ReadOnly: true})},
+		},
+	})
+	registerBuiltin(Story{
+		Name: "Checkbox",
+		Variants: []Variant{
+			{Name: "Unchecked", Component: otter.Checkbox(otter.CheckboxProps{Name: "terms", Label: "Accept the terms"})},
+			{Name: "Checked", Component: otter.Checkbox(otter.CheckboxProps{Name: "newsletter", Label: "Subscribe to the newsletter", Checked: true})},
+		},
+	})
+	registerBuiltin(Story{
+		Name: "Label",
+		Variants: []Variant{
+			{Name: "Default", Component: otter.Label(otter.LabelProps{Label: "Name"})},
+			{Name: "Required", Component: otter.Label(otter.LabelProps{Label: "Name", Required: true})},
+		},
+	})
+	registerBuiltin(Story{
+		Name: "ErrorAlert",
+		Variants: []Variant{
+			{Name: "Default", Component: otter.ErrorAlert(errors.New("Something went wrong, please try again"))},
+		},
+	})
+	registerBuiltin(Story{
+		Name:        "ToastHandler",
+		Description: "Click the buttons to show the toasts",
+		Variants: []Variant{
+			{Name: "Levels", Component: templ.Join(otter.ToastHandler(), toastButtons())},
+		},
+	})
+	registerBuiltin(Story{
+		Name:        "LanguageSelector",
+		Description: "The options are labeled with the `locale.{locale}` translations",
+		Variants: []Variant{
+			{Name: "Default", Component: i18n.LanguageSelector()},
+		},
+	})
+}
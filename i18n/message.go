@@ -0,0 +1,422 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/a-h/templ"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// A message is parsed into nodes following the ICU MessageFormat syntax, supporting simple `{name}` arguments
+// along with `{name, plural, ...}`, `{name, selectordinal, ...}` and `{name, select, ...}` ones.
+// Inside plural options `#` is replaced with the number. Numbers are formatted in the locale of the context
+// and `{`, `}`, `#` and the backslash itself can be escaped with a backslash, a backslash before any other character is kept
+type messageNode interface{}
+
+type textNode string
+
+type argNode struct {
+	name string
+}
+
+type hashNode struct{}
+
+type choiceNode struct {
+	name string
+	// kind is either plural, selectordinal or select
+	kind    string
+	offset  float64
+	options map[string][]messageNode
+}
+
+var pluralKeywords = map[string]bool{
+	"zero":  true,
+	"one":   true,
+	"two":   true,
+	"few":   true,
+	"many":  true,
+	"other": true,
+}
+
+var pluralForms = map[plural.Form]string{
+	plural.Other: "other",
+	plural.Zero:  "zero",
+	plural.One:   "one",
+	plural.Two:   "two",
+	plural.Few:   "few",
+	plural.Many:  "many",
+}
+
+type messageParser struct {
+	runes []rune
+	pos   int
+}
+
+func parseMessage(message string) ([]messageNode, error) {
+	p := &messageParser{runes: []rune(message)}
+	return p.parseNodes(false, false)
+}
+
+func (p *messageParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%s at position %d", fmt.Sprintf(format, args...), p.pos)
+}
+
+func (p *messageParser) skipSpaces() {
+	for p.pos < len(p.runes) && unicode.IsSpace(p.runes[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *messageParser) peek() (rune, bool) {
+	if p.pos >= len(p.runes) {
+		return 0, false
+	}
+	return p.runes[p.pos], true
+}
+
+func (p *messageParser) expect(c rune) error {
+	next, ok := p.peek()
+	if !ok {
+		return p.errorf("expected \"%c\" but the message ended", c)
+	}
+	if next != c {
+		return p.errorf("expected \"%c\" but found \"%c\"", c, next)
+	}
+	p.pos++
+	return nil
+}
+
+// parseWord reads until a space or a syntax character
+func (p *messageParser) parseWord() string {
+	start := p.pos
+	for p.pos < len(p.runes) {
+		c := p.runes[p.pos]
+		if unicode.IsSpace(c) || c == '{' || c == '}' || c == ',' {
+			break
+		}
+		p.pos++
+	}
+	return string(p.runes[start:p.pos])
+}
+
+// parseNodes parses a message until its end, or until the closing brace when it's nested into an option
+func (p *messageParser) parseNodes(inPlural bool, nested bool) ([]messageNode, error) {
+	nodes := []messageNode{}
+	text := strings.Builder{}
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, textNode(text.String()))
+			text.Reset()
+		}
+	}
+	for p.pos < len(p.runes) {
+		c := p.runes[p.pos]
+		switch {
+		case c == '\\' && p.pos+1 < len(p.runes) && strings.ContainsRune(`{}#\`, p.runes[p.pos+1]):
+			text.WriteRune(p.runes[p.pos+1])
+			p.pos += 2
+		case c == '{':
+			flush()
+			node, err := p.parseArgument(inPlural)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+		case c == '}':
+			if !nested {
+				return nil, p.errorf("closing variable before opening one")
+			}
+			flush()
+			p.pos++
+			return nodes, nil
+		case c == '#' && inPlural:
+			flush()
+			nodes = append(nodes, hashNode{})
+			p.pos++
+		default:
+			text.WriteRune(c)
+			p.pos++
+		}
+	}
+	if nested {
+		return nil, p.errorf("missing closing \"}\" of option")
+	}
+	flush()
+	return nodes, nil
+}
+
+func (p *messageParser) parseArgument(inPlural bool) (messageNode, error) {
+	p.pos++
+	p.skipSpaces()
+	name := p.parseWord()
+	if name == "" {
+		return nil, p.errorf("missing variable name between {}")
+	}
+	p.skipSpaces()
+	next, ok := p.peek()
+	if !ok {
+		return nil, p.errorf("missing closing \"}\" of variable \"%s\"", name)
+	}
+	if next == '}' {
+		p.pos++
+		return argNode{name: name}, nil
+	}
+	if next != ',' {
+		return nil, p.errorf("unexpected \"%c\" after variable \"%s\"", next, name)
+	}
+	p.pos++
+	p.skipSpaces()
+	kind := p.parseWord()
+	switch kind {
+	case "plural", "selectordinal", "select":
+	case "":
+		return nil, p.errorf("missing type of variable \"%s\"", name)
+	default:
+		return nil, p.errorf("unsupported type \"%s\" of variable \"%s\", valid types are [plural, selectordinal, select]", kind, name)
+	}
+	p.skipSpaces()
+	if err := p.expect(','); err != nil {
+		return nil, err
+	}
+	return p.parseOptions(name, kind, inPlural)
+}
+
+func (p *messageParser) parseOptions(name string, kind string, inPlural bool) (messageNode, error) {
+	node := choiceNode{
+		name:    name,
+		kind:    kind,
+		options: map[string][]messageNode{},
+	}
+	for {
+		p.skipSpaces()
+		next, ok := p.peek()
+		if !ok {
+			return nil, p.errorf("missing closing \"}\" of variable \"%s\"", name)
+		}
+		if next == '}' {
+			p.pos++
+			break
+		}
+		selector := p.parseWord()
+		if kind == "plural" && strings.HasPrefix(selector, "offset:") && len(node.options) == 0 {
+			offset, err := strconv.ParseFloat(strings.TrimPrefix(selector, "offset:"), 64)
+			if err != nil {
+				return nil, p.errorf("invalid offset \"%s\" of variable \"%s\"", selector, name)
+			}
+			node.offset = offset
+			continue
+		}
+		if selector == "" {
+			return nil, p.errorf("missing option of variable \"%s\"", name)
+		}
+		if kind != "select" && !pluralKeywords[selector] {
+			if _, err := strconv.ParseFloat(strings.TrimPrefix(selector, "="), 64); err != nil || selector[0] != '=' {
+				return nil, p.errorf("invalid %s option \"%s\" of variable \"%s\", valid options are [zero, one, two, few, many, other] or =N", kind, selector, name)
+			}
+		}
+		if _, exists := node.options[selector]; exists {
+			return nil, p.errorf("duplicated option \"%s\" of variable \"%s\"", selector, name)
+		}
+		p.skipSpaces()
+		if err := p.expect('{'); err != nil {
+			return nil, err
+		}
+		message, err := p.parseNodes(inPlural || kind != "select", true)
+		if err != nil {
+			return nil, err
+		}
+		node.options[selector] = message
+	}
+	if _, ok := node.options["other"]; !ok {
+		return nil, p.errorf("missing \"other\" option of variable \"%s\"", name)
+	}
+	return node, nil
+}
+
+// numeric is a numeric replacement, text keeps the digits as they would be written in go
+// and raw the original value so it can be formatted in the locale
+type numeric struct {
+	value float64
+	text  string
+	raw   any
+}
+
+func toNumber(v any) (numeric, bool) {
+	switch n := v.(type) {
+	case int:
+		return numeric{float64(n), strconv.FormatInt(int64(n), 10), v}, true
+	case int8:
+		return numeric{float64(n), strconv.FormatInt(int64(n), 10), v}, true
+	case int16:
+		return numeric{float64(n), strconv.FormatInt(int64(n), 10), v}, true
+	case int32:
+		return numeric{float64(n), strconv.FormatInt(int64(n), 10), v}, true
+	case int64:
+		return numeric{float64(n), strconv.FormatInt(n, 10), v}, true
+	case uint:
+		return numeric{float64(n), strconv.FormatUint(uint64(n), 10), v}, true
+	case uint8:
+		return numeric{float64(n), strconv.FormatUint(uint64(n), 10), v}, true
+	case uint16:
+		return numeric{float64(n), strconv.FormatUint(uint64(n), 10), v}, true
+	case uint32:
+		return numeric{float64(n), strconv.FormatUint(uint64(n), 10), v}, true
+	case uint64:
+		return numeric{float64(n), strconv.FormatUint(n, 10), v}, true
+	case float32:
+		return numeric{float64(n), strconv.FormatFloat(float64(n), 'f', -1, 32), v}, true
+	case float64:
+		return numeric{n, strconv.FormatFloat(n, 'f', -1, 64), v}, true
+	}
+	return numeric{}, false
+}
+
+func (n numeric) minus(offset float64) numeric {
+	if offset == 0 {
+		return n
+	}
+	value := n.value - offset
+	return numeric{value, strconv.FormatFloat(value, 'f', -1, 64), value}
+}
+
+// pluralForm returns the CLDR plural category of the number in the language
+func (n numeric) pluralForm(rules *plural.Rules, tag language.Tag) string {
+	integer, fraction, _ := strings.Cut(strings.TrimPrefix(n.text, "-"), ".")
+	// operands too large to fit are passed modulo 10,000,000
+	i, err := strconv.Atoi(integer)
+	if err != nil || len(integer) > 7 {
+		i, _ = strconv.Atoi(integer[max(len(integer)-7, 0):])
+	}
+	trimmed := strings.TrimRight(fraction, "0")
+	f, _ := strconv.Atoi(fraction[:min(len(fraction), 7)])
+	t, _ := strconv.Atoi(trimmed[:min(len(trimmed), 7)])
+	return pluralForms[rules.MatchPlural(tag, i, len(fraction), len(trimmed), f, t)]
+}
+
+type messageRenderer struct {
+	tag          language.Tag
+	printer      *message.Printer
+	raw          bool
+	replacements Replacements
+}
+
+func (m messageRenderer) value(name string) (any, error) {
+	val, ok := m.replacements[name]
+	if !ok {
+		return nil, fmt.Errorf("missing variable \"%s\" value", name)
+	}
+	return val, nil
+}
+
+// render turns the nodes into components, hash is the number of the closest plural, if any
+func (m messageRenderer) render(nodes []messageNode, hash *numeric) ([]templ.Component, error) {
+	chunks := make([]templ.Component, 0, len(nodes))
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case textNode:
+			chunks = append(chunks, strChunk(string(n), m.raw))
+		case hashNode:
+			chunks = append(chunks, strChunk(m.printer.Sprint(number.Decimal(hash.raw)), m.raw))
+		case argNode:
+			val, err := m.value(n.name)
+			if err != nil {
+				return nil, err
+			}
+			chunk, err := m.renderValue(n.name, val)
+			if err != nil {
+				return nil, err
+			}
+			chunks = append(chunks, chunk)
+		case choiceNode:
+			val, err := m.value(n.name)
+			if err != nil {
+				return nil, err
+			}
+			option, optionHash, err := m.choose(n, val)
+			if err != nil {
+				return nil, err
+			}
+			if optionHash == nil {
+				optionHash = hash
+			}
+			optionChunks, err := m.render(option, optionHash)
+			if err != nil {
+				return nil, err
+			}
+			chunks = append(chunks, optionChunks...)
+		}
+	}
+	return chunks, nil
+}
+
+func (m messageRenderer) renderValue(name string, val any) (templ.Component, error) {
+	switch v := val.(type) {
+	case templ.Component:
+		return v, nil
+	case string:
+		return strChunk(v, m.raw), nil
+	case []rune:
+		return strChunk(string(v), m.raw), nil
+	case []byte:
+		return strChunk(string(v), m.raw), nil
+	}
+	if n, ok := toNumber(val); ok {
+		return strChunk(m.printer.Sprint(number.Decimal(n.raw)), m.raw), nil
+	}
+	return nil, fmt.Errorf("variable \"%s\" of type %T not supported", name, val)
+}
+
+// choose returns the option matching the value, along with the number replacing `#` for plurals
+func (m messageRenderer) choose(node choiceNode, val any) ([]messageNode, *numeric, error) {
+	if node.kind == "select" {
+		key := fmt.Sprint(val)
+		if option, ok := node.options[key]; ok {
+			return option, nil, nil
+		}
+		return node.options["other"], nil, nil
+	}
+
+	n, ok := toNumber(val)
+	if !ok {
+		return nil, nil, fmt.Errorf("variable \"%s\" of type %T must be a number to be used in %s", node.name, val, node.kind)
+	}
+	if math.IsNaN(n.value) || math.IsInf(n.value, 0) {
+		return nil, nil, fmt.Errorf("variable \"%s\" must be a finite number to be used in %s", node.name, node.kind)
+	}
+	for selector, option := range node.options {
+		if !strings.HasPrefix(selector, "=") {
+			continue
+		}
+		exact, _ := strconv.ParseFloat(selector[1:], 64)
+		if exact == n.value {
+			shown := n.minus(node.offset)
+			return option, &shown, nil
+		}
+	}
+	shown := n.minus(node.offset)
+	rules := plural.Cardinal
+	if node.kind == "selectordinal" {
+		rules = plural.Ordinal
+	}
+	if option, ok := node.options[shown.pluralForm(rules, m.tag)]; ok {
+		return option, &shown, nil
+	}
+	return node.options["other"], &shown, nil
+}
+
+// localeTag returns the language tag of the locale of the context
+func localeTag(ctx context.Context) language.Tag {
+	tag, err := language.Parse(FromCtx(ctx))
+	if err != nil {
+		return language.Und
+	}
+	return tag
+}
@@ -15,73 +15,59 @@ type localeKeyType string
 
 var localeKey localeKeyType = "locale"
 
-func parseAcceptLanguage(header string) []string {
-	langs := strings.Split(header, ",")
-
-	for i, l := range langs {
-		if strings.Contains(l, ";") {
-			langs[i] = strings.Split(l, ";")[0]
-		}
-	}
-	return langs
-}
-
+// Middleware sets the locale of the request, taken from the path prefix when using SetPrefixRouting,
+// otherwise the one chosen with the LanguageSelector or the one that best matches the Accept-Language header
 func Middleware(next http.Handler) http.Handler {
 	if len(supportedLocales) == 0 {
 		utils.Throw("invalid i18n middleware initialization, before initializing the middleware make sure to add your locales with i18n.AddLocale()")
 	}
-	defaultLocale := defaultLocale
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/set-locale" {
 			locale := r.FormValue("locale")
-			w.Header().Add("HX-Refresh", "true")
+			// with prefix routing the page is only in the new locale under its url
+			if path := r.FormValue("path"); prefixRouting && isLocalPath(path) {
+				w.Header().Add("HX-Redirect", LocaleHref(negotiate(locale), path))
+			} else {
+				w.Header().Add("HX-Refresh", "true")
+			}
 			SetLocale(w, r, locale)
 			return
 		}
-		locale := "INVALID"
-		cookie, err := r.Cookie(cookieName)
-		if err != nil {
-			header := r.Header["Accept-Language"]
-			if len(header) == 0 {
-				locale = defaultLocale
-			} else {
-				langs := parseAcceptLanguage(header[0])
-
-				for _, lang := range langs {
-					if lang == "*" {
-						locale = defaultLocale
-						break
-					}
-
-					for _, l := range supportedLocales {
-						if lang == l {
-							locale = lang
-						}
-					}
-				}
-
-				if locale == "INVALID" {
-					locale = defaultLocale
-				}
-			}
-		} else {
-			lang := cookie.Value
-			if lang == "*" {
-				locale = defaultLocale
-			}
-
-			for _, l := range supportedLocales {
-				if lang == l {
-					locale = lang
-				}
-			}
+		locale, prefixed := "", false
+		if prefixRouting {
+			r, locale, prefixed = withLocalePrefix(r)
+		}
+		if !prefixed {
+			locale = negotiateRequest(r)
 		}
 
 		ctx := context.WithValue(r.Context(), localeKey, locale)
+		ctx = withLocalizedRequest(ctx, r)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// negotiateRequest returns the locale chosen with the LanguageSelector, or the one that best matches
+// the Accept-Language header
+func negotiateRequest(r *http.Request) string {
+	cookie, err := r.Cookie(cookieName)
+	if err == nil {
+		if cookie.Value == "*" {
+			return defaultLocale
+		}
+		return negotiate(cookie.Value)
+	}
+	if header := r.Header.Get("Accept-Language"); header != "" {
+		return negotiate(header)
+	}
+	return defaultLocale
+}
+
+// isLocalPath reports whether the path is within the site, so it can't be used to redirect elsewhere
+func isLocalPath(path string) bool {
+	return strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "//") && !strings.HasPrefix(path, "/\\")
+}
+
 func SetLocale(w http.ResponseWriter, r *http.Request, lang string) {
 	cookie := http.Cookie{
 		Name:  cookieName,
@@ -94,6 +80,12 @@ func SetLocale(w http.ResponseWriter, r *http.Request, lang string) {
 	http.SetCookie(w, &cookie)
 }
 
+// WithLocale returns a copy of the context using the given locale, useful to render content outside of a request
+// such as emails
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey, locale)
+}
+
 func FromCtx(ctx context.Context) string {
 	l := ctx.Value(localeKey)
 	if l == nil {
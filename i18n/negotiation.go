@@ -0,0 +1,109 @@
+package i18n
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+var (
+	matcherMu      sync.Mutex
+	matcher        language.Matcher
+	matcherLocales []string
+	fallbacks      sync.Map
+)
+
+// normalizeLocale returns the canonical form of the BCP 47 tag, like es-MX for es_mx
+func normalizeLocale(locale string) (string, error) {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", fmt.Errorf("invalid locale \"%s\", locales must be BCP 47 tags like en or es-MX: %w", locale, err)
+	}
+	return tag.String(), nil
+}
+
+// resetNegotiation drops the matcher and fallback chains so they are built again with the current locales
+func resetNegotiation() {
+	matcherMu.Lock()
+	matcher = nil
+	matcherMu.Unlock()
+	fallbacks.Clear()
+}
+
+// localeMatcher returns the matcher of the supported locales, with the default one first
+// as it's the one returned when nothing matches
+func localeMatcher() (language.Matcher, []string) {
+	matcherMu.Lock()
+	defer matcherMu.Unlock()
+	if matcher != nil && len(matcherLocales) > 0 && matcherLocales[0] == defaultLocale {
+		return matcher, matcherLocales
+	}
+	matcherLocales = make([]string, 0, len(supportedLocales)+1)
+	matcherLocales = append(matcherLocales, defaultLocale)
+	for _, locale := range supportedLocales {
+		if locale != defaultLocale {
+			matcherLocales = append(matcherLocales, locale)
+		}
+	}
+	tags := make([]language.Tag, len(matcherLocales))
+	for i, locale := range matcherLocales {
+		tags[i] = language.Make(locale)
+	}
+	matcher = language.NewMatcher(tags)
+	return matcher, matcherLocales
+}
+
+// negotiate returns the supported locale that best matches the Accept-Language header or locale tag,
+// honoring the q-values and falling back to the regional variants or base language, or to the default locale
+func negotiate(accept string) string {
+	if slices.Contains(supportedLocales, accept) {
+		return accept
+	}
+	tags, _, err := language.ParseAcceptLanguage(accept)
+	if err != nil || len(tags) == 0 {
+		return defaultLocale
+	}
+	m, locales := localeMatcher()
+	_, index, confidence := m.Match(tags...)
+	if confidence == language.No {
+		return defaultLocale
+	}
+	return locales[index]
+}
+
+// fallbackChain returns the locales whose translations are used for the locale, in order.
+// It goes through the parents of the tag and ends with the default locale, like es-MX, es-419, es, en
+func fallbackChain(locale string) []string {
+	if chain, ok := fallbacks.Load(locale); ok {
+		return chain.([]string)
+	}
+	chain := []string{}
+	if _, ok := translations[locale]; ok {
+		chain = append(chain, locale)
+	}
+	tag, err := language.Parse(locale)
+	if err == nil {
+		for parent := tag.Parent(); !parent.IsRoot(); parent = parent.Parent() {
+			if _, ok := translations[parent.String()]; ok && !slices.Contains(chain, parent.String()) {
+				chain = append(chain, parent.String())
+			}
+		}
+	}
+	if defaultLocale != "" && !slices.Contains(chain, defaultLocale) {
+		chain = append(chain, defaultLocale)
+	}
+	fallbacks.Store(locale, chain)
+	return chain
+}
+
+// lookup returns the translation of the key going through the fallback chain of the locale
+func lookup(locale string, key string) (string, bool) {
+	for _, l := range fallbackChain(locale) {
+		if content := translations[l][key]; content != "" {
+			return content, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,80 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func resetLocales(t *testing.T) {
+	translations = map[string]map[string]string{}
+	supportedLocales = []string{}
+	defaultLocale = ""
+	resetNegotiation()
+	t.Cleanup(func() {
+		translations = map[string]map[string]string{}
+		supportedLocales = []string{}
+		defaultLocale = ""
+		resetNegotiation()
+	})
+}
+
+func TestNegotiation(t *testing.T) {
+	resetLocales(t)
+	AddLocaleBytes("en-US", []byte(`{"hello": "Hello", "bye": "Bye", "color": "Color"}`))
+	AddLocaleBytes("es", []byte(`{"hello": "Hola", "bye": "Adiós"}`))
+	AddLocaleBytes("es_mx", []byte(`{"hello": "Qué onda"}`))
+	AddLocaleBytes("fr", []byte(`{"hello": "Bonjour"}`))
+
+	assert.Equal(t, []string{"en-US", "es", "es-MX", "fr"}, Locales())
+
+	testcases := []struct {
+		name   string
+		accept string
+		cookie string
+		locale string
+	}{
+		{name: "no header", locale: "en-US"},
+		{name: "exact", accept: "fr", locale: "fr"},
+		{name: "q-values", accept: "fr;q=0.5, es;q=0.9", locale: "es"},
+		{name: "best match rather than last", accept: "es, fr;q=0.1", locale: "es"},
+		{name: "regional", accept: "es-MX", locale: "es-MX"},
+		{name: "regional fallback", accept: "es-ES", locale: "es"},
+		{name: "closest region", accept: "es-AR", locale: "es-MX"},
+		{name: "base language", accept: "en", locale: "en-US"},
+		{name: "unsupported", accept: "ja", locale: "en-US"},
+		{name: "wildcard", accept: "*", locale: "en-US"},
+		{name: "cookie", accept: "fr", cookie: "es-MX", locale: "es-MX"},
+		{name: "invalid cookie", cookie: "invalid tag!", locale: "en-US"},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			if testcase.accept != "" {
+				r.Header.Set("Accept-Language", testcase.accept)
+			}
+			if testcase.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: cookieName, Value: testcase.cookie})
+			}
+			locale := ""
+			Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				locale = FromCtx(r.Context())
+			})).ServeHTTP(httptest.NewRecorder(), r)
+			assert.Equal(t, testcase.locale, locale)
+		})
+	}
+
+	ctx := WithLocale(context.Background(), "es-MX")
+	assert.Equal(t, "Qué onda", Translation(ctx, "hello"))
+	assert.Equal(t, "Adiós", Translation(ctx, "bye"))
+	assert.Equal(t, "Color", Translation(ctx, "color"))
+	assert.Equal(t, "missing", Translation(ctx, "missing"))
+
+	date := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, DateTimeLocale("es", date, DateStyleLong), DateTimeLocale("es-MX", date, DateStyleLong))
+	assert.Equal(t, DateTimeLocale("en", date, DateStyleLong), DateTimeLocale("ja", date, DateStyleLong))
+}
@@ -0,0 +1,116 @@
+package i18n
+
+import (
+	"context"
+	"math"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+func printer(ctx context.Context) *message.Printer {
+	return message.NewPrinter(localeTag(ctx))
+}
+
+// Number formats the number with the digit grouping and decimal separator of the locale, like 1,234.5 or 1.234,5
+func Number(ctx context.Context, value any) string {
+	return printer(ctx).Sprint(number.Decimal(value))
+}
+
+// currencyPatterns place the symbol, ¤, around the amount, n, following CLDR. Languages missing here use the english pattern
+var currencyPatterns = map[string]string{
+	"en": "¤n",
+	"es": "n\u00a0¤",
+	"fr": "n\u00a0¤",
+	"it": "n\u00a0¤",
+	"de": "n\u00a0¤",
+	"pt": "¤\u00a0n",
+}
+
+// ungroupedThousands are the languages that don't group the digits of four digit amounts, like 1234,50
+var ungroupedThousands = map[string]bool{
+	"es": true,
+}
+
+// Currency formats the amount with the symbol of the ISO 4217 currency code placed as the locale does, like $1,234.50
+// or 1.234,50 €. When the code is invalid the amount is formatted as a number followed by the code
+func Currency(ctx context.Context, value any, code string) string {
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return Number(ctx, value) + " " + code
+	}
+	tag := localeTag(ctx)
+	base, _ := tag.Base()
+	pattern, ok := currencyPatterns[base.String()]
+	if !ok {
+		pattern = currencyPatterns["en"]
+	}
+	p := message.NewPrinter(tag)
+
+	scale, _ := currency.Standard.Rounding(unit)
+	amount := p.Sprint(number.Decimal(value, number.Scale(scale)))
+	if ungroupedThousands[base.String()] {
+		ungrouped := p.Sprint(number.Decimal(value, number.Scale(scale), number.NoSeparator()))
+		digits := strings.TrimPrefix(ungrouped, "-")
+		if end := strings.IndexFunc(digits, func(r rune) bool { return !unicode.IsDigit(r) }); end >= 0 {
+			digits = digits[:end]
+		}
+		if len(digits) <= 4 {
+			amount = ungrouped
+		}
+	}
+	sign := ""
+	if strings.HasPrefix(amount, "-") {
+		sign, amount = "-", amount[1:]
+	}
+	symbol := p.Sprint(currency.Symbol(unit))
+	return sign + strings.Replace(strings.Replace(pattern, "n", amount, 1), "¤", symbol, 1)
+}
+
+// Percent formats the ratio as a percentage, 0.25 being 25%
+func Percent(ctx context.Context, value any) string {
+	return printer(ctx).Sprint(number.Percent(value))
+}
+
+// compactSuffixes are the CLDR short suffixes for thousands, millions, billions and trillions.
+// The suffixes are separated with a no-break space when the language does. An empty suffix means the language doesn't abbreviate that magnitude
+var compactSuffixes = map[string][4]string{
+	"en": {"K", "M", "B", "T"},
+	"es": {"\u00a0mil", "\u00a0M", "\u00a0mil\u00a0M", "\u00a0B"},
+	"fr": {"\u00a0k", "\u00a0M", "\u00a0Md", "\u00a0Bn"},
+	"it": {"", "\u00a0Mln", "\u00a0Mrd", "\u00a0Bln"},
+	"de": {"\u00a0Tsd.", "\u00a0Mio.", "\u00a0Mrd.", "\u00a0Bio."},
+	"pt": {"\u00a0mil", "\u00a0mi", "\u00a0bi", "\u00a0tri"},
+}
+
+// Compact formats large numbers in their short form, like 1.2K or 3,4 M, using the english suffixes
+// for languages it doesn't know
+func Compact(ctx context.Context, value float64) string {
+	tag := localeTag(ctx)
+	base, _ := tag.Base()
+	suffixes, ok := compactSuffixes[base.String()]
+	if !ok {
+		suffixes = compactSuffixes["en"]
+	}
+	p := message.NewPrinter(tag)
+
+	abs := math.Abs(value)
+	magnitude := -1
+	for i := range suffixes {
+		if abs >= math.Pow(1000, float64(i+1)) {
+			magnitude = i
+		}
+	}
+	// 999,999 would round to 1000K
+	if magnitude >= 0 && magnitude < len(suffixes)-1 && math.Round(abs/math.Pow(1000, float64(magnitude+1))*10)/10 >= 1000 {
+		magnitude++
+	}
+	if magnitude < 0 || suffixes[magnitude] == "" {
+		return p.Sprint(number.Decimal(value, number.MaxFractionDigits(1)))
+	}
+	scaled := value / math.Pow(1000, float64(magnitude+1))
+	return p.Sprint(number.Decimal(scaled, number.MaxFractionDigits(1))) + suffixes[magnitude]
+}
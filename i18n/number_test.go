@@ -0,0 +1,55 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumberFormatting(t *testing.T) {
+	testcases := []struct {
+		locale   string
+		number   string
+		currency string
+		percent  string
+		compact  []string
+	}{
+		{locale: "en", number: "1,234,567.5", currency: "$1,234.50", percent: "25%", compact: []string{"999", "1.2K", "3.5M", "1M"}},
+		{locale: "es", number: "1.234.567,5", currency: "1234,50\u00a0US$", percent: "25\u00a0%", compact: []string{"999", "1,2\u00a0mil", "3,5\u00a0M", "1\u00a0M"}},
+		{locale: "de", number: "1.234.567,5", currency: "1.234,50\u00a0$", percent: "25\u00a0%", compact: []string{"999", "1,2\u00a0Tsd.", "3,5\u00a0Mio.", "1\u00a0Mio."}},
+		{locale: "it", number: "1.234.567,5", currency: "1.234,50\u00a0USD", percent: "25%", compact: []string{"999", "1.234", "3,5\u00a0Mln", "1\u00a0Mln"}},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.locale, func(t *testing.T) {
+			ctx := WithLocale(context.Background(), testcase.locale)
+			assert.Equal(t, testcase.number, Number(ctx, 1234567.5))
+			assert.Equal(t, testcase.currency, Currency(ctx, 1234.5, "USD"))
+			assert.Equal(t, testcase.percent, Percent(ctx, 0.25))
+			compact := []string{Compact(ctx, 999), Compact(ctx, 1234), Compact(ctx, 3_500_000), Compact(ctx, 999_999)}
+			assert.Equal(t, testcase.compact, compact)
+		})
+	}
+}
+
+func TestCurrencyPlacement(t *testing.T) {
+	testcases := []struct {
+		locale   string
+		amount   float64
+		code     string
+		expected string
+	}{
+		{locale: "en", amount: -1234.5, code: "USD", expected: "-$1,234.50"},
+		{locale: "en", amount: 1234.6, code: "JPY", expected: "¥1,235"},
+		{locale: "es", amount: 12345.5, code: "USD", expected: "12.345,50\u00a0US$"},
+		{locale: "de", amount: 1234.5, code: "EUR", expected: "1.234,50\u00a0€"},
+		{locale: "pt", amount: 1234.5, code: "USD", expected: "US$\u00a01.234,50"},
+		{locale: "en", amount: 1234.5, code: "XXXX", expected: "1,234.5 XXXX"},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.locale+" "+testcase.code, func(t *testing.T) {
+			ctx := WithLocale(context.Background(), testcase.locale)
+			assert.Equal(t, testcase.expected, Currency(ctx, testcase.amount, testcase.code))
+		})
+	}
+}
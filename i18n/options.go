@@ -4,5 +4,9 @@ var defaultLocale string
 
 // SetDefault changes the default locale, by default the default locale is the first one added
 func SetDefault(locale string) {
+	if normalized, err := normalizeLocale(locale); err == nil {
+		locale = normalized
+	}
 	defaultLocale = locale
+	resetNegotiation()
 }
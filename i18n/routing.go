@@ -0,0 +1,130 @@
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var (
+	prefixRouting bool
+	baseURL       string
+)
+
+// SetPrefixRouting serves the pages under /{locale}/..., the middleware takes the locale from the prefix and strips it
+// so the routes are registered without it. Requests without prefix keep negotiating the locale.
+// Build the links with LocalizedHref and list the alternates with HrefLangTags
+func SetPrefixRouting(enabled bool) {
+	prefixRouting = enabled
+}
+
+// SetBaseURL sets the origin used for the absolute urls of HrefLangTags, like https://example.com.
+// Without it the alternates are relative, the origin isn't taken from the request as the Host and
+// X-Forwarded-Proto headers are controlled by the client and would end up in the cached pages
+func SetBaseURL(url string) {
+	baseURL = strings.TrimSuffix(url, "/")
+}
+
+type requestKeyType string
+
+var requestKey requestKeyType = "request"
+
+// localizedRequest is the path and query of the request without the locale prefix
+type localizedRequest struct {
+	path  string
+	query string
+}
+
+// stripLocalePrefix returns the locale of the path prefix along with the path without it
+func stripLocalePrefix(path string) (string, string, bool) {
+	segment, rest, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if segment == "" {
+		return "", path, false
+	}
+	locale, err := normalizeLocale(segment)
+	if err != nil {
+		return "", path, false
+	}
+	if _, ok := translations[locale]; !ok {
+		return "", path, false
+	}
+	return locale, "/" + rest, true
+}
+
+// withLocalePrefix reads the locale from the path prefix, returning the request without it
+func withLocalePrefix(r *http.Request) (*http.Request, string, bool) {
+	locale, path, ok := stripLocalePrefix(r.URL.Path)
+	if !ok {
+		return r, "", false
+	}
+	r2 := new(http.Request)
+	*r2 = *r
+	r2.URL = new(url.URL)
+	*r2.URL = *r.URL
+	r2.URL.Path = path
+	r2.URL.RawPath = ""
+	return r2, locale, true
+}
+
+func withLocalizedRequest(ctx context.Context, r *http.Request) context.Context {
+	return context.WithValue(ctx, requestKey, localizedRequest{
+		path:  r.URL.Path,
+		query: r.URL.RawQuery,
+	})
+}
+
+func localizedRequestFromCtx(ctx context.Context) localizedRequest {
+	request, ok := ctx.Value(requestKey).(localizedRequest)
+	if !ok {
+		return localizedRequest{path: "/"}
+	}
+	return request
+}
+
+// LocaleHref returns the path in the given locale, adding the locale prefix when prefix routing is enabled.
+// Only absolute paths are prefixed, relative ones like ?page=2 already resolve under the current locale
+func LocaleHref(locale string, path string) string {
+	if !prefixRouting || (path != "" && !strings.HasPrefix(path, "/")) {
+		return path
+	}
+	if path == "/" || path == "" {
+		return "/" + locale
+	}
+	return "/" + locale + path
+}
+
+// LocalizedHref returns the path in the locale of the context, adding the locale prefix when prefix routing is enabled
+func LocalizedHref(ctx context.Context, path string) string {
+	return LocaleHref(FromCtx(ctx), path)
+}
+
+// selectorVals are the values sent by the LanguageSelector along with the locale, the current page without prefix
+// so it can be redirected to the new locale when using prefix routing
+func selectorVals(ctx context.Context) string {
+	if !prefixRouting {
+		return "{}"
+	}
+	request := localizedRequestFromCtx(ctx)
+	path := request.path
+	if request.query != "" {
+		path += "?" + request.query
+	}
+	vals, _ := json.Marshal(map[string]string{"path": path})
+	return string(vals)
+}
+
+// alternateHref returns the url of the current page in the locale, or of the page without prefix
+// when the locale is empty. It's absolute when the base url is set
+func alternateHref(ctx context.Context, locale string) string {
+	request := localizedRequestFromCtx(ctx)
+	href := request.path
+	if locale != "" {
+		href = LocaleHref(locale, href)
+	}
+	if request.query != "" {
+		href += "?" + request.query
+	}
+	return baseURL + href
+}
@@ -0,0 +1,88 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixRouting(t *testing.T) {
+	resetLocales(t)
+	AddLocaleBytes("en", []byte(`{}`))
+	AddLocaleBytes("es-MX", []byte(`{}`))
+	SetPrefixRouting(true)
+	t.Cleanup(func() { SetPrefixRouting(false) })
+
+	testcases := []struct {
+		target string
+		accept string
+		locale string
+		path   string
+	}{
+		{target: "/es-MX/users/1", locale: "es-MX", path: "/users/1"},
+		{target: "/es-mx/users/1", locale: "es-MX", path: "/users/1"},
+		{target: "/en", locale: "en", path: "/"},
+		{target: "/users", accept: "es-MX", locale: "es-MX", path: "/users"},
+		{target: "/fr/users", locale: "en", path: "/fr/users"},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.target, func(t *testing.T) {
+			r := httptest.NewRequest("GET", testcase.target, nil)
+			if testcase.accept != "" {
+				r.Header.Set("Accept-Language", testcase.accept)
+			}
+			locale, path, href := "", "", ""
+			Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				locale = FromCtx(r.Context())
+				path = r.URL.Path
+				href = LocalizedHref(r.Context(), "/about")
+			})).ServeHTTP(httptest.NewRecorder(), r)
+			assert.Equal(t, testcase.locale, locale)
+			assert.Equal(t, testcase.path, path)
+			assert.Equal(t, "/"+testcase.locale+"/about", href)
+		})
+	}
+
+	t.Run("hreflang", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "http://example.com/es-MX/users?page=2", nil)
+		b := strings.Builder{}
+		Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = HrefLangTags().Render(r.Context(), &b)
+		})).ServeHTTP(httptest.NewRecorder(), r)
+		assert.Contains(t, b.String(), `<link rel="alternate" hreflang="en" href="/en/users?page=2">`)
+		assert.Contains(t, b.String(), `<link rel="alternate" hreflang="es-MX" href="/es-MX/users?page=2">`)
+		assert.Contains(t, b.String(), `<link rel="alternate" hreflang="x-default" href="/users?page=2">`)
+	})
+
+	t.Run("hreflang with base url", func(t *testing.T) {
+		SetBaseURL("https://example.com/")
+		t.Cleanup(func() { SetBaseURL("") })
+		r := httptest.NewRequest("GET", "http://attacker.com/es-MX/users?page=2", nil)
+		r.Header.Set("X-Forwarded-Proto", "gopher")
+		b := strings.Builder{}
+		Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = HrefLangTags().Render(r.Context(), &b)
+		})).ServeHTTP(httptest.NewRecorder(), r)
+		assert.Contains(t, b.String(), `<link rel="alternate" hreflang="en" href="https://example.com/en/users?page=2">`)
+		assert.Contains(t, b.String(), `<link rel="alternate" hreflang="x-default" href="https://example.com/users?page=2">`)
+		assert.NotContains(t, b.String(), "attacker.com")
+	})
+
+	t.Run("set locale", func(t *testing.T) {
+		form := url.Values{"locale": {"es-MX"}, "path": {"/users?page=2"}}
+		r := httptest.NewRequest("POST", "/set-locale", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		Middleware(http.NotFoundHandler()).ServeHTTP(w, r)
+		assert.Equal(t, "/es-MX/users?page=2", w.Header().Get("HX-Redirect"))
+	})
+
+	assert.Equal(t, "/es-MX", LocaleHref("es-MX", "/"))
+	assert.Equal(t, "?page=2", LocaleHref("es-MX", "?page=2"))
+	assert.Equal(t, "/en/about", LocalizedHref(context.Background(), "/about"))
+}
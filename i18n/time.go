@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/goodsign/monday"
+	"golang.org/x/text/language"
 )
 
 type DateStyle string
@@ -63,5 +64,18 @@ func DateTime(ctx context.Context, t time.Time, style DateStyle) string {
 	return DateTimeLocale(FromCtx(ctx), t, style)
 }
 func DateTimeLocale(locale string, t time.Time, style DateStyle) string {
+	locale = dateLocale(locale)
 	return monday.Format(t, layout[locale][style], monday.Locale(mondayLocale[locale]))
 }
+
+// dateLocale returns the locale whose date layouts are used, falling back to the base language and then to english
+func dateLocale(locale string) string {
+	if _, ok := layout[locale]; ok {
+		return locale
+	}
+	base, _ := language.Make(locale).Base()
+	if _, ok := layout[base.String()]; ok {
+		return base.String()
+	}
+	return "en"
+}
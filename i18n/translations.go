@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 
 	"github.com/a-h/templ"
 	"github.com/martinmunillas/otter/utils"
@@ -46,19 +47,33 @@ func flattenJson(input map[string]interface{}) (map[string]string, error) {
 var translations = make(map[string]map[string]string, 2)
 var supportedLocales = make([]string, 0, 2)
 
+// Locales returns the locales added with AddLocale, in the order they were added
+func Locales() []string {
+	return slices.Clone(supportedLocales)
+}
+
 func addLocale(locale string, m map[string]interface{}) error {
+	locale, err := normalizeLocale(locale)
+	if err != nil {
+		return err
+	}
 	translation, err := flattenJson(m)
 	if err != nil {
 		return err
 	}
-	supportedLocales = append(supportedLocales, locale)
+	if !slices.Contains(supportedLocales, locale) {
+		supportedLocales = append(supportedLocales, locale)
+	}
 	translations[locale] = translation
 	if defaultLocale == "" {
 		defaultLocale = locale
 	}
+	resetNegotiation()
 	return nil
 }
 
+// AddLocale adds the translations of the locale, a BCP 47 tag like en or es-MX. Keys missing on a regional locale
+// fall back to its base language, and then to the default locale
 func AddLocale(locale string, r io.Reader) {
 	m := map[string]interface{}{}
 	err := json.NewDecoder(r).Decode(&m)
@@ -117,67 +132,30 @@ func errorThrower(err error) templ.Component {
 
 func t(ctx context.Context, key string, raw bool, replacements ...Replacements) templ.Component {
 	str := Translation(ctx, key)
-	if len(replacements) == 0 || str == key {
+	if str == key {
 		return strChunk(str, raw)
 	}
 	if len(replacements) > 1 {
 		return errorThrower(fmt.Errorf("invalid translation \"%s\" call: more than one replacements map provided", key))
 	}
-	runes := []rune(str)
-
-	chunks := make([]templ.Component, 0, 1)
-
-	currentStr := ""
-	currentVarName := ""
-	isCollectingVarName := false
-	for i, c := range runes {
-		isEscaped := i > 0 && runes[i-1] == '\\'
-		if c == '{' && !isEscaped {
-			if isCollectingVarName {
-				return errorThrower(fmt.Errorf("invalid translation \"%s\" format: opening variable before closing previous", key))
-			}
-			if currentStr != "" {
-				chunks = append(chunks, strChunk(currentStr, raw))
-				currentStr = ""
-			}
-			isCollectingVarName = true
-			continue
-		}
-		if c == '}' && !isEscaped {
-			if !isCollectingVarName {
-				return errorThrower(fmt.Errorf("invalid translation \"%s\" format: closing variable before opening one", key))
-			}
-			if currentVarName == "" {
-				return errorThrower(fmt.Errorf("invalid translation \"%s\" format: missing variable name between {}", key))
-			}
-			val, ok := replacements[0][currentVarName]
-			if !ok {
-				return errorThrower(fmt.Errorf("invalid translation \"%s\" call: missing variable \"%s\" value", key, currentVarName))
-			}
-			switch v := val.(type) {
-			case templ.Component:
-				chunks = append(chunks, v)
-			case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
-				chunks = append(chunks, strChunk(fmt.Sprintf("%d", v), raw))
-			case string, []rune, []byte:
-				chunks = append(chunks, strChunk(fmt.Sprintf("%s", v), raw))
-			default:
-				return errorThrower(fmt.Errorf("invalid translation \"%s\" call: variable \"%s\" of type %t not supported", key, currentVarName, v))
-
-			}
-			currentVarName = ""
-			isCollectingVarName = false
-			continue
-		}
-
-		if isCollectingVarName {
-			currentVarName += string(c)
-		} else {
-			currentStr += string(c)
-		}
+	// messages without variables are parsed too, for their escapes
+	values := Replacements{}
+	if len(replacements) == 1 {
+		values = replacements[0]
+	}
+	nodes, err := parseMessage(str)
+	if err != nil {
+		return errorThrower(fmt.Errorf("invalid translation \"%s\" format: %w", key, err))
 	}
-	if currentStr != "" {
-		chunks = append(chunks, strChunk(currentStr, raw))
+	renderer := messageRenderer{
+		tag:          localeTag(ctx),
+		printer:      printer(ctx),
+		raw:          raw,
+		replacements: values,
+	}
+	chunks, err := renderer.render(nodes, nil)
+	if err != nil {
+		return errorThrower(fmt.Errorf("invalid translation \"%s\" call: %w", key, err))
 	}
 	return chunksRender(chunks)
 }
@@ -194,20 +172,24 @@ func RawT(ctx context.Context, key string, replacements ...Replacements) templ.C
 
 // Translation returns the translated translation as a string
 func Translation(ctx context.Context, key string) string {
-	locale := FromCtx(ctx)
-	content := translations[locale][key]
-	if content == "" {
+	content, ok := lookup(FromCtx(ctx), key)
+	if !ok {
 		return key
 	}
 	return content
 }
 
+// TranslationOr returns the translated translation as a string, or the fallback when the key isn't defined,
+// meant for the built-in texts apps may not translate
+func TranslationOr(ctx context.Context, key string, fallback string) string {
+	content, ok := lookup(FromCtx(ctx), key)
+	if !ok {
+		return fallback
+	}
+	return content
+}
+
 // ErrorT returns an error type with the translated translation as content
 func ErrorT(ctx context.Context, key string) error {
-	locale := FromCtx(ctx)
-	content := translations[locale][key]
-	if content == "" {
-		return errors.New(key)
-	}
-	return errors.New(content)
+	return errors.New(Translation(ctx, key))
 }
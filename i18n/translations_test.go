@@ -1,6 +1,8 @@
 package i18n
 
 import (
+	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -60,3 +62,93 @@ func TestFlattenJson(t *testing.T) {
 		}
 	}
 }
+
+func TestT(t *testing.T) {
+	err := addLocale("en", map[string]interface{}{
+		"greeting": "Hello {name}!",
+		"escaped":  "Use \\{name\\} {name}",
+		"items":    "{count, plural, =0 {No items} one {# item} other {# items}}",
+		"guests":   "{count, plural, offset:1 =0 {Nobody} =1 {{host}} one {{host} and # guest} other {{host} and # guests}}",
+		"place":    "{n, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}",
+		"invite":   "{gender, select, female {She} male {He} other {They}} invited you",
+		"nested":   "{count, plural, one {{gender, select, female {her # item} other {their # item}}} other {# items}}",
+		"broken":   "{count, plural, one {# item}}",
+		"unclosed": "{count, plural, one {# item} other {# items}",
+		"unknown":  "{count, number}",
+		"path":     "C:\\temp \\\\ \\# \\{",
+		"literal":  "Use \\{braces\\}",
+	})
+	assert.NoError(t, err)
+	err = addLocale("pl", map[string]interface{}{
+		"items": "{count, plural, one {# plik} few {# pliki} many {# plików} other {# pliku}}",
+	})
+	assert.NoError(t, err)
+
+	testcases := []struct {
+		locale       string
+		key          string
+		replacements Replacements
+		out          string
+		err          string
+	}{
+		{locale: "en", key: "greeting", replacements: Replacements{"name": "Otter"}, out: "Hello Otter!"},
+		{locale: "en", key: "escaped", replacements: Replacements{"name": "Otter"}, out: "Use {name} Otter"},
+		{locale: "en", key: "items", replacements: Replacements{"count": 0}, out: "No items"},
+		{locale: "en", key: "items", replacements: Replacements{"count": 1}, out: "1 item"},
+		{locale: "en", key: "items", replacements: Replacements{"count": 5}, out: "5 items"},
+		{locale: "en", key: "items", replacements: Replacements{"count": 1.5}, out: "1.5 items"},
+		{locale: "en", key: "items", replacements: Replacements{"count": 1234}, out: "1,234 items"},
+		{locale: "en", key: "greeting", replacements: Replacements{"name": 1234.5}, out: "Hello 1,234.5!"},
+		{locale: "en", key: "guests", replacements: Replacements{"count": 1, "host": "Ana"}, out: "Ana"},
+		{locale: "en", key: "guests", replacements: Replacements{"count": 2, "host": "Ana"}, out: "Ana and 1 guest"},
+		{locale: "en", key: "guests", replacements: Replacements{"count": 4, "host": "Ana"}, out: "Ana and 3 guests"},
+		{locale: "en", key: "place", replacements: Replacements{"n": 1}, out: "1st"},
+		{locale: "en", key: "place", replacements: Replacements{"n": 22}, out: "22nd"},
+		{locale: "en", key: "place", replacements: Replacements{"n": 13}, out: "13th"},
+		{locale: "en", key: "invite", replacements: Replacements{"gender": "female"}, out: "She invited you"},
+		{locale: "en", key: "invite", replacements: Replacements{"gender": "unknown"}, out: "They invited you"},
+		{locale: "en", key: "nested", replacements: Replacements{"count": 1, "gender": "female"}, out: "her 1 item"},
+		{locale: "pl", key: "items", replacements: Replacements{"count": 1}, out: "1 plik"},
+		{locale: "pl", key: "items", replacements: Replacements{"count": 3}, out: "3 pliki"},
+		{locale: "pl", key: "items", replacements: Replacements{"count": 5}, out: "5 plików"},
+		{locale: "pl", key: "items", replacements: Replacements{"count": 22}, out: "22 pliki"},
+		{locale: "pl", key: "items", replacements: Replacements{"count": 1.5}, out: "1,5 pliku"},
+		{locale: "en", key: "path", out: "C:\\temp \\ # {"},
+		{locale: "en", key: "literal", out: "Use {braces}"},
+		{locale: "en", key: "items", err: "missing variable \"count\" value"},
+		{locale: "en", key: "greeting", replacements: Replacements{}, err: "missing variable \"name\" value"},
+		{locale: "en", key: "items", replacements: Replacements{"count": "one"}, err: "must be a number"},
+		{locale: "en", key: "broken", replacements: Replacements{"count": 1}, err: "missing \"other\" option"},
+		{locale: "en", key: "unclosed", replacements: Replacements{"count": 1}, err: "missing closing \"}\""},
+		{locale: "en", key: "unknown", replacements: Replacements{"count": 1}, err: "unsupported type \"number\""},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.locale+" "+testcase.key, func(t *testing.T) {
+			ctx := WithLocale(context.Background(), testcase.locale)
+			b := strings.Builder{}
+			component := T(ctx, testcase.key)
+			if testcase.replacements != nil {
+				component = T(ctx, testcase.key, testcase.replacements)
+			}
+			err := component.Render(ctx, &b)
+			if testcase.err != "" {
+				assert.ErrorContains(t, err, testcase.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testcase.out, b.String())
+		})
+	}
+}
+
+func TestTranslationOr(t *testing.T) {
+	err := addLocale("en", map[string]interface{}{
+		"otter.modal.close": "Dismiss",
+	})
+	assert.NoError(t, err)
+
+	ctx := WithLocale(context.Background(), "en")
+	assert.Equal(t, "Dismiss", TranslationOr(ctx, "otter.modal.close", "Close"))
+	assert.Equal(t, "Cancel", TranslationOr(ctx, "otter.modal.cancel", "Cancel"))
+}
@@ -0,0 +1,40 @@
+package otter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnlyOptions(t *testing.T) {
+	value := "b"
+	options := []Option{{Label: "A", Value: "a"}, {Label: "B", Value: "b"}}
+	props := InputProps{Name: "letter", Value: &value, ReadOnly: true}
+
+	b := strings.Builder{}
+	err := Select(SelectProps{InputProps: props, Options: options}).Render(context.Background(), &b)
+	assert.NoError(t, err)
+	assert.Contains(t, b.String(), `name="letter" disabled>`)
+	assert.Contains(t, b.String(), `<input type="hidden" name="letter" value="b">`)
+
+	b.Reset()
+	err = RadioGroup(SelectProps{InputProps: props, Options: options}).Render(context.Background(), &b)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(b.String(), "disabled"))
+	assert.Contains(t, b.String(), `<input type="hidden" name="letter" value="b">`)
+
+	b.Reset()
+	err = CheckboxGroup(CheckboxGroupProps{InputProps: props, Options: options, Values: []string{"a", "b"}}).Render(context.Background(), &b)
+	assert.NoError(t, err)
+	assert.Contains(t, b.String(), `<input type="hidden" name="letter" value="a">`)
+	assert.Contains(t, b.String(), `<input type="hidden" name="letter" value="b">`)
+
+	b.Reset()
+	props.ReadOnly = false
+	err = Select(SelectProps{InputProps: props, Options: options}).Render(context.Background(), &b)
+	assert.NoError(t, err)
+	assert.NotContains(t, b.String(), "disabled")
+	assert.NotContains(t, b.String(), `type="hidden"`)
+}
@@ -0,0 +1,106 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/martinmunillas/otter/utils"
+)
+
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusRunning Status = "running"
+	StatusDead    Status = "dead"
+)
+
+const defaultMaxAttempts = 5
+
+type Job struct {
+	ID          int64
+	Name        string
+	Payload     json.RawMessage
+	Status      Status
+	Attempts    int
+	MaxAttempts int
+	RunAt       time.Time
+	LastError   string
+}
+
+type handler = func(ctx context.Context, payload json.RawMessage) error
+
+var handlers = map[string]handler{}
+
+// Register adds a job handler, the payload of the enqueued jobs is decoded from JSON into T
+func Register[T any](name string, h func(ctx context.Context, payload T) error) {
+	if _, exists := handlers[name]; exists {
+		utils.Throw(fmt.Sprintf("job `%s` is already registered", name))
+	}
+	handlers[name] = func(ctx context.Context, raw json.RawMessage) error {
+		payload := new(T)
+		err := json.Unmarshal(raw, payload)
+		if err != nil {
+			return fmt.Errorf("error decoding payload: %w", err)
+		}
+		return h(ctx, *payload)
+	}
+}
+
+var store Store
+
+// SetStore sets the store where jobs are enqueued and claimed from
+func SetStore(s Store) {
+	store = s
+}
+
+type Option func(job *Job)
+
+// Delay postpones the first run of the job
+func Delay(d time.Duration) Option {
+	return func(job *Job) {
+		job.RunAt = time.Now().Add(d)
+	}
+}
+
+// At sets the time of the first run of the job
+func At(t time.Time) Option {
+	return func(job *Job) {
+		job.RunAt = t
+	}
+}
+
+// MaxAttempts sets how many times the job is tried before being sent to the dead-letter, 5 by default
+func MaxAttempts(attempts int) Option {
+	return func(job *Job) {
+		job.MaxAttempts = attempts
+	}
+}
+
+// Enqueue adds a job to be run by the worker pool
+func Enqueue(ctx context.Context, name string, payload any, options ...Option) error {
+	if store == nil {
+		return errors.New("missing job store, make sure to set one with jobs.SetStore()")
+	}
+	if _, exists := handlers[name]; !exists {
+		return fmt.Errorf("unknown job `%s`, make sure to register it with jobs.Register()", name)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error encoding payload of job `%s`: %w", name, err)
+	}
+	job := Job{
+		Name:        name,
+		Payload:     data,
+		Status:      StatusPending,
+		MaxAttempts: defaultMaxAttempts,
+		RunAt:       time.Now(),
+	}
+	for _, option := range options {
+		option(&job)
+	}
+	return store.Enqueue(ctx, job)
+}
@@ -0,0 +1,115 @@
+package jobs
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps the jobs in memory, meant to be used in tests
+type MemoryStore struct {
+	mu     sync.Mutex
+	lastID int64
+	jobs   []Job
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (m *MemoryStore) Enqueue(ctx context.Context, job Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastID++
+	job.ID = m.lastID
+	m.jobs = append(m.jobs, job)
+	return nil
+}
+
+func (m *MemoryStore) Claim(ctx context.Context) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	next := -1
+	for i, job := range m.jobs {
+		if job.Status != StatusPending || job.RunAt.After(now) {
+			continue
+		}
+		if next == -1 || job.RunAt.Before(m.jobs[next].RunAt) {
+			next = i
+		}
+	}
+	if next == -1 {
+		return nil, nil
+	}
+	m.jobs[next].Status = StatusRunning
+	m.jobs[next].Attempts++
+	job := m.jobs[next]
+	return &job, nil
+}
+
+func (m *MemoryStore) update(id int64, fn func(job *Job)) {
+	for i := range m.jobs {
+		if m.jobs[i].ID == id {
+			fn(&m.jobs[i])
+			return
+		}
+	}
+}
+
+func (m *MemoryStore) Complete(ctx context.Context, job Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.jobs {
+		if m.jobs[i].ID == job.ID {
+			m.jobs = append(m.jobs[:i], m.jobs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) Retry(ctx context.Context, job Job, runAt time.Time, jobErr error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.update(job.ID, func(j *Job) {
+		j.Status = StatusPending
+		j.RunAt = runAt
+		j.LastError = jobErr.Error()
+	})
+	return nil
+}
+
+func (m *MemoryStore) Bury(ctx context.Context, job Job, jobErr error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.update(job.ID, func(j *Job) {
+		j.Status = StatusDead
+		j.LastError = jobErr.Error()
+	})
+	return nil
+}
+
+// Jobs returns a copy of the jobs that haven't completed yet, ordered by id
+func (m *MemoryStore) Jobs() []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	jobs := make([]Job, len(m.jobs))
+	copy(jobs, m.jobs)
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].ID < jobs[j].ID
+	})
+	return jobs
+}
+
+// Dead returns a copy of the jobs in the dead-letter
+func (m *MemoryStore) Dead() []Job {
+	dead := []Job{}
+	for _, job := range m.Jobs() {
+		if job.Status == StatusDead {
+			dead = append(dead, job)
+		}
+	}
+	return dead
+}
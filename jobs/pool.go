@@ -0,0 +1,147 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/martinmunillas/otter/log"
+)
+
+type PoolOptions struct {
+	// Workers is the amount of jobs run concurrently, 4 by default
+	Workers int
+	// PollInterval is how often idle workers look for new jobs, 1 second by default
+	PollInterval time.Duration
+	// Backoff returns how long to wait before retrying a job that failed on the given attempt,
+	// by default it starts at 10 seconds and doubles on every attempt up to 1 hour
+	Backoff func(attempt int) time.Duration
+	Logger  *slog.Logger
+}
+
+type Pool struct {
+	options PoolOptions
+}
+
+// NewPool creates a worker pool that runs the jobs of the store set with SetStore
+func NewPool(options PoolOptions) *Pool {
+	if options.Workers <= 0 {
+		options.Workers = 4
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = time.Second
+	}
+	if options.Backoff == nil {
+		options.Backoff = exponentialBackoff
+	}
+	if options.Logger == nil {
+		options.Logger = log.Default()
+	}
+	return &Pool{
+		options: options,
+	}
+}
+
+func exponentialBackoff(attempt int) time.Duration {
+	backoff := 10 * time.Second
+	for i := 1; i < attempt && backoff < time.Hour; i++ {
+		backoff *= 2
+	}
+	return min(backoff, time.Hour)
+}
+
+// Run starts the workers and blocks until the context is done and the jobs in progress finish
+func (p *Pool) Run(ctx context.Context) error {
+	if store == nil {
+		return errors.New("missing job store, make sure to set one with jobs.SetStore()")
+	}
+	p.options.Logger.Info(fmt.Sprintf("Starting %d job workers", p.options.Workers))
+	var wg sync.WaitGroup
+	for range p.options.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx)
+		}()
+	}
+	wg.Wait()
+	p.options.Logger.Info("Job workers stopped")
+	return nil
+}
+
+func (p *Pool) work(ctx context.Context) {
+	for {
+		ran, err := p.runNext(ctx)
+		if err != nil {
+			p.options.Logger.Error(fmt.Sprintf("error claiming job: %v", err))
+		}
+		if ran {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.options.PollInterval):
+		}
+	}
+}
+
+// Drain runs the due jobs one by one until there are none left, useful to run the enqueued jobs in tests
+func (p *Pool) Drain(ctx context.Context) error {
+	if store == nil {
+		return errors.New("missing job store, make sure to set one with jobs.SetStore()")
+	}
+	for {
+		ran, err := p.runNext(ctx)
+		if err != nil {
+			return err
+		}
+		if !ran {
+			return nil
+		}
+	}
+}
+
+func (p *Pool) runNext(ctx context.Context) (bool, error) {
+	if ctx.Err() != nil {
+		return false, nil
+	}
+	job, err := store.Claim(ctx)
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	// jobs in progress are allowed to finish when the pool is stopped
+	jobCtx := context.WithoutCancel(ctx)
+	logger := p.options.Logger.With("job", job.Name, "id", job.ID, "attempt", job.Attempts)
+
+	jobErr := runHandler(jobCtx, *job)
+	if jobErr == nil {
+		logger.Debug("Job ran successfully")
+		return true, store.Complete(jobCtx, *job)
+	}
+
+	if job.Attempts >= job.MaxAttempts {
+		logger.Error(fmt.Sprintf("Job failed, moving it to the dead-letter: %v", jobErr))
+		return true, store.Bury(jobCtx, *job, jobErr)
+	}
+	backoff := p.options.Backoff(job.Attempts)
+	logger.Warn(fmt.Sprintf("Job failed, retrying in %s: %v", backoff, jobErr))
+	return true, store.Retry(jobCtx, *job, time.Now().Add(backoff), jobErr)
+}
+
+func runHandler(ctx context.Context, job Job) (err error) {
+	h, exists := handlers[job.Name]
+	if !exists {
+		return fmt.Errorf("unknown job `%s`, make sure to register it with jobs.Register()", job.Name)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return h(ctx, job.Payload)
+}
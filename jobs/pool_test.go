@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoolDrain(t *testing.T) {
+	type payload struct {
+		Email string `json:"email"`
+	}
+	sent := []string{}
+	Register("test.sendEmail", func(ctx context.Context, p payload) error {
+		if p.Email == "fail@example.com" {
+			return errors.New("smtp unavailable")
+		}
+		sent = append(sent, p.Email)
+		return nil
+	})
+
+	memory := NewMemoryStore()
+	SetStore(memory)
+	pool := NewPool(PoolOptions{
+		Backoff: func(attempt int) time.Duration { return 0 },
+	})
+	ctx := context.Background()
+
+	assert.NoError(t, Enqueue(ctx, "test.sendEmail", payload{Email: "ok@example.com"}))
+	assert.NoError(t, Enqueue(ctx, "test.sendEmail", payload{Email: "fail@example.com"}, MaxAttempts(3)))
+	assert.NoError(t, Enqueue(ctx, "test.sendEmail", payload{Email: "later@example.com"}, Delay(time.Hour)))
+	assert.Error(t, Enqueue(ctx, "test.unknown", nil))
+
+	assert.NoError(t, pool.Drain(ctx))
+	assert.Equal(t, []string{"ok@example.com"}, sent)
+
+	dead := memory.Dead()
+	if assert.Len(t, dead, 1) {
+		assert.Equal(t, 3, dead[0].Attempts)
+		assert.Equal(t, "smtp unavailable", dead[0].LastError)
+	}
+	assert.Len(t, memory.Jobs(), 2)
+}
@@ -0,0 +1,109 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// MigrationUp creates the jobs table, add it to your migrations with
+// `migrate.AddMigration("{timestamp}_otter_jobs", jobs.MigrationUp, jobs.MigrationDown)`
+func MigrationUp(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE "otter_jobs" (
+			"id" BIGSERIAL PRIMARY KEY,
+			"name" VARCHAR NOT NULL,
+			"payload" JSONB NOT NULL,
+			"status" VARCHAR NOT NULL DEFAULT 'pending',
+			"attempts" INT NOT NULL DEFAULT 0,
+			"max_attempts" INT NOT NULL,
+			"run_at" TIMESTAMPTZ NOT NULL,
+			"locked_at" TIMESTAMPTZ,
+			"last_error" TEXT NOT NULL DEFAULT '',
+			"created_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+		);
+		CREATE INDEX "otter_jobs_claim_idx" ON "otter_jobs" ("status", "run_at");
+	`)
+	return err
+}
+
+// MigrationDown drops the jobs table
+func MigrationDown(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE "otter_jobs";`)
+	return err
+}
+
+type PostgresStore struct {
+	db *sql.DB
+	// StaleAfter is the time after which a running job is considered abandoned, for example because
+	// its worker crashed, and can be claimed again. 1 hour by default
+	StaleAfter time.Duration
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{
+		db:         db,
+		StaleAfter: time.Hour,
+	}
+}
+
+func (p *PostgresStore) Enqueue(ctx context.Context, job Job) error {
+	_, err := p.db.ExecContext(
+		ctx,
+		`INSERT INTO otter_jobs (name, payload, status, max_attempts, run_at) VALUES ($1, $2, $3, $4, $5)`,
+		job.Name, []byte(job.Payload), StatusPending, job.MaxAttempts, job.RunAt,
+	)
+	return err
+}
+
+func (p *PostgresStore) Claim(ctx context.Context) (*Job, error) {
+	job := Job{}
+	var payload []byte
+	err := p.db.QueryRowContext(
+		ctx,
+		`UPDATE otter_jobs
+		SET status = 'running', attempts = attempts + 1, locked_at = now()
+		WHERE id = (
+			SELECT id FROM otter_jobs
+			WHERE (status = 'pending' AND run_at <= now())
+			OR (status = 'running' AND locked_at < $1)
+			ORDER BY run_at, id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING id, name, payload, status, attempts, max_attempts, run_at, last_error`,
+		time.Now().Add(-p.StaleAfter),
+	).Scan(&job.ID, &job.Name, &payload, &job.Status, &job.Attempts, &job.MaxAttempts, &job.RunAt, &job.LastError)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	job.Payload = payload
+	return &job, nil
+}
+
+func (p *PostgresStore) Complete(ctx context.Context, job Job) error {
+	_, err := p.db.ExecContext(ctx, `DELETE FROM otter_jobs WHERE id = $1`, job.ID)
+	return err
+}
+
+func (p *PostgresStore) Retry(ctx context.Context, job Job, runAt time.Time, jobErr error) error {
+	_, err := p.db.ExecContext(
+		ctx,
+		`UPDATE otter_jobs SET status = 'pending', run_at = $2, locked_at = NULL, last_error = $3 WHERE id = $1`,
+		job.ID, runAt, jobErr.Error(),
+	)
+	return err
+}
+
+func (p *PostgresStore) Bury(ctx context.Context, job Job, jobErr error) error {
+	_, err := p.db.ExecContext(
+		ctx,
+		`UPDATE otter_jobs SET status = 'dead', locked_at = NULL, last_error = $2 WHERE id = $1`,
+		job.ID, jobErr.Error(),
+	)
+	return err
+}
@@ -0,0 +1,19 @@
+package jobs
+
+import (
+	"context"
+	"time"
+)
+
+type Store interface {
+	// Enqueue saves a new pending job
+	Enqueue(ctx context.Context, job Job) error
+	// Claim marks the next due job as running and increments its attempts, returns nil when there are no due jobs
+	Claim(ctx context.Context) (*Job, error)
+	// Complete removes a job that ran successfully
+	Complete(ctx context.Context, job Job) error
+	// Retry marks a failed job as pending again to be run at the given time
+	Retry(ctx context.Context, job Job, runAt time.Time, jobErr error) error
+	// Bury moves a job that ran out of attempts into the dead-letter
+	Bury(ctx context.Context, job Job, jobErr error) error
+}
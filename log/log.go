@@ -1,12 +1,46 @@
 package log
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/a-h/templ/cmd/templ/sloghandler"
 )
 
+type Format string
+
+const (
+	// FormatPretty is the human-readable format, meant for development
+	FormatPretty Format = "pretty"
+	FormatText   Format = "text"
+	FormatJSON   Format = "json"
+)
+
+const redacted = "[REDACTED]"
+
+// DefaultRedact are the attribute keys redacted by default
+var DefaultRedact = []string{"password", "token", "cookie", "secret", "authorization"}
+
+type Options struct {
+	// Format of the output, FormatPretty by default
+	Format    Format
+	Level     slog.Leveler
+	AddSource bool
+	// Redact lists the attribute keys whose values are hidden, a key is redacted when it contains any of them
+	// ignoring the case. Values following them in the message, like password=... or "token": "...", are hidden too.
+	// DefaultRedact is used when nil, use an empty slice to disable redaction
+	Redact []string
+	// Writer is where the logs are written, os.Stderr by default
+	Writer io.Writer
+}
+
 func NewLogger(verbose bool) *slog.Logger {
 	loggingLevel := slog.LevelInfo.Level()
 	if verbose {
@@ -18,3 +52,159 @@ func NewLogger(verbose bool) *slog.Logger {
 	}))
 	return logger
 }
+
+// New creates a logger with the given options
+func New(options Options) *slog.Logger {
+	if options.Writer == nil {
+		options.Writer = os.Stderr
+	}
+	if options.Redact == nil {
+		options.Redact = DefaultRedact
+	}
+	handlerOptions := &slog.HandlerOptions{
+		AddSource: options.AddSource,
+		Level:     options.Level,
+	}
+
+	var handler slog.Handler
+	switch options.Format {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(options.Writer, handlerOptions)
+	case FormatText:
+		handler = slog.NewTextHandler(options.Writer, handlerOptions)
+	default:
+		handler = sloghandler.NewHandler(options.Writer, handlerOptions)
+	}
+	if len(options.Redact) > 0 {
+		// the pretty handler doesn't run ReplaceAttr, so the attributes are redacted before reaching it
+		handler = newRedactHandler(handler, options.Redact)
+	}
+	return slog.New(handler)
+}
+
+// redactPattern matches the values assigned to the keys in a message, the first group being everything before the value
+func redactPattern(keys []string) *regexp.Regexp {
+	quoted := make([]string, len(keys))
+	for i, key := range keys {
+		quoted[i] = regexp.QuoteMeta(key)
+	}
+	return regexp.MustCompile(`(?i)([\w-]*(?:` + strings.Join(quoted, "|") + `)[\w-]*["']?\s*[:=]\s*(?:(?:bearer|basic)\s+)?)("[^"]*"|'[^']*'|[^\s,;&]+)`)
+}
+
+// redactHandler hides the values of the redacted keys, on the attributes of every format and on the messages,
+// as they are often built with fmt.Sprintf
+type redactHandler struct {
+	slog.Handler
+	keys    []string
+	pattern *regexp.Regexp
+}
+
+func newRedactHandler(handler slog.Handler, keys []string) redactHandler {
+	lowerKeys := make([]string, len(keys))
+	for i, key := range keys {
+		lowerKeys[i] = strings.ToLower(key)
+	}
+	return redactHandler{Handler: handler, keys: lowerKeys, pattern: redactPattern(keys)}
+}
+
+func (h redactHandler) Handle(ctx context.Context, record slog.Record) error {
+	redactedRecord := slog.NewRecord(record.Time, record.Level, h.pattern.ReplaceAllString(record.Message, "${1}"+redacted), record.PC)
+	record.Attrs(func(a slog.Attr) bool {
+		redactedRecord.AddAttrs(h.redact(a))
+		return true
+	})
+	return h.Handler.Handle(ctx, redactedRecord)
+}
+
+func (h redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redactedAttrs := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redactedAttrs[i] = h.redact(a)
+	}
+	return redactHandler{Handler: h.Handler.WithAttrs(redactedAttrs), keys: h.keys, pattern: h.pattern}
+}
+
+func (h redactHandler) WithGroup(name string) slog.Handler {
+	return redactHandler{Handler: h.Handler.WithGroup(name), keys: h.keys, pattern: h.pattern}
+}
+
+// redact hides the value of the attribute when its key contains any of the redacted keys, going through groups
+func (h redactHandler) redact(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		group := a.Value.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, attr := range group {
+			attrs[i] = h.redact(attr)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(attrs...)}
+	}
+	key := strings.ToLower(a.Key)
+	for _, k := range h.keys {
+		if strings.Contains(key, k) {
+			return slog.String(a.Key, redacted)
+		}
+	}
+	return a
+}
+
+// OptionsFromEnv reads the options from the LOG_FORMAT (pretty, text or json), LOG_LEVEL (debug, info, warn or error)
+// and LOG_SOURCE (true or false) env variables
+func OptionsFromEnv() (Options, error) {
+	options := Options{
+		Format: FormatPretty,
+		Level:  slog.LevelInfo,
+	}
+	if format := os.Getenv("LOG_FORMAT"); format != "" {
+		switch Format(strings.ToLower(format)) {
+		case FormatPretty, FormatText, FormatJSON:
+			options.Format = Format(strings.ToLower(format))
+		default:
+			return options, fmt.Errorf("invalid LOG_FORMAT \"%s\", valid formats are [pretty, text, json]", format)
+		}
+	}
+	if level := os.Getenv("LOG_LEVEL"); level != "" {
+		var l slog.Level
+		err := l.UnmarshalText([]byte(level))
+		if err != nil {
+			return options, fmt.Errorf("invalid LOG_LEVEL \"%s\", valid levels are [debug, info, warn, error]", level)
+		}
+		options.Level = l
+	}
+	if source := os.Getenv("LOG_SOURCE"); source != "" {
+		addSource, err := strconv.ParseBool(source)
+		if err != nil {
+			return options, fmt.Errorf("invalid LOG_SOURCE \"%s\"", source)
+		}
+		options.AddSource = addSource
+	}
+	return options, nil
+}
+
+var (
+	defaultMu     sync.Mutex
+	defaultLogger *slog.Logger
+)
+
+// Default returns the logger used by otter, unless it's changed with SetDefault it's created from the
+// env variables described on OptionsFromEnv
+func Default() *slog.Logger {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultLogger == nil {
+		options, err := OptionsFromEnv()
+		defaultLogger = New(options)
+		if err != nil {
+			defaultLogger.Error(err.Error())
+		}
+	}
+	return defaultLogger
+}
+
+// SetDefault changes the logger used by otter, it also becomes the default of the slog package
+func SetDefault(logger *slog.Logger) {
+	defaultMu.Lock()
+	defaultLogger = logger
+	defaultMu.Unlock()
+	slog.SetDefault(logger)
+}
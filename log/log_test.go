@@ -0,0 +1,101 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedaction(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(Options{Format: FormatJSON, Writer: buf})
+
+	logger.With("api_token", "abc").WithGroup("request").Info(
+		`login with password=hunter2, Authorization: Bearer abc.def and {"refreshToken": "xyz"} from /reset?token=123&next=/`,
+		"user", "otter",
+		"Cookie", "session=1",
+		slog.Group("db", "password", "secret"),
+	)
+
+	entry := map[string]any{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t,
+		`login with password=[REDACTED], Authorization: Bearer [REDACTED] and {"refreshToken": [REDACTED]} from /reset?token=[REDACTED]&next=/`,
+		entry["msg"],
+	)
+	assert.Equal(t, "[REDACTED]", entry["api_token"])
+	assert.Equal(t, map[string]any{
+		"user":   "otter",
+		"Cookie": "[REDACTED]",
+		"db":     map[string]any{"password": "[REDACTED]"},
+	}, entry["request"])
+}
+
+func TestRedactionKeepsOtherMessages(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(Options{Format: FormatJSON, Writer: buf})
+	logger.Info("token expired for user 1")
+
+	entry := map[string]any{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "token expired for user 1", entry["msg"])
+}
+
+func TestRedactionDisabled(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(Options{Format: FormatJSON, Writer: buf, Redact: []string{}})
+	logger.Info("password=hunter2", "token", "abc")
+
+	entry := map[string]any{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "password=hunter2", entry["msg"])
+	assert.Equal(t, "abc", entry["token"])
+}
+
+func TestOptionsFromEnv(t *testing.T) {
+	testcases := []struct {
+		name    string
+		format  string
+		level   string
+		source  string
+		options Options
+		err     bool
+	}{
+		{name: "defaults", options: Options{Format: FormatPretty, Level: slog.LevelInfo}},
+		{name: "values", format: "JSON", level: "debug", source: "true", options: Options{Format: FormatJSON, Level: slog.LevelDebug, AddSource: true}},
+		{name: "text", format: "text", level: "WARN", options: Options{Format: FormatText, Level: slog.LevelWarn}},
+		{name: "invalid format", format: "xml", err: true},
+		{name: "invalid level", level: "loud", err: true},
+		{name: "invalid source", source: "maybe", err: true},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			t.Setenv("LOG_FORMAT", testcase.format)
+			t.Setenv("LOG_LEVEL", testcase.level)
+			t.Setenv("LOG_SOURCE", testcase.source)
+			options, err := OptionsFromEnv()
+			if testcase.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testcase.options, options)
+		})
+	}
+}
+
+func TestRedactionPretty(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(Options{Writer: buf})
+	logger.With("api_token", "abc").Info("login with password=hunter2", "password", "hunter2", "cookie", "sess=1", "user", "otter")
+
+	output := buf.String()
+	assert.Contains(t, output, "login with password=[REDACTED]")
+	assert.Contains(t, output, "user=otter")
+	assert.NotContains(t, output, "hunter2")
+	assert.NotContains(t, output, "sess=1")
+	assert.NotContains(t, output, "abc")
+}
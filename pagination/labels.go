@@ -0,0 +1,57 @@
+package pagination
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/martinmunillas/otter/i18n"
+)
+
+// navAttributes boosts the links when there's no target, a partial response would replace the layout otherwise
+func navAttributes(props Props) templ.Attributes {
+	if props.Target == "" {
+		return templ.Attributes{"hx-boost": "true"}
+	}
+	return templ.Attributes{
+		"hx-target":   props.Target,
+		"hx-push-url": "true",
+	}
+}
+
+func linkAttributes(props Props, href string) templ.Attributes {
+	if props.Target == "" {
+		return templ.Attributes{}
+	}
+	return templ.Attributes{"hx-get": href}
+}
+
+// prevHref and nextHref keep the locale prefix of the current page when using prefix routing
+func prevHref(ctx context.Context, info Info) string {
+	if info.PrevCursor != "" {
+		return i18n.LocalizedHref(ctx, info.CursorHref(info.PrevCursor))
+	}
+	return i18n.LocalizedHref(ctx, info.Href(info.Page-1))
+}
+
+func nextHref(ctx context.Context, info Info) string {
+	if info.NextCursor != "" {
+		return i18n.LocalizedHref(ctx, info.CursorHref(info.NextCursor))
+	}
+	return i18n.LocalizedHref(ctx, info.Href(info.Page+1))
+}
+
+// pageOf translates pagination.pageOf, which may pluralize on the page numbers, falling back to the english text
+// when the app doesn't define it
+func pageOf(ctx context.Context, replacements i18n.Replacements) templ.Component {
+	const key = "pagination.pageOf"
+	if i18n.Translation(ctx, key) != key {
+		return i18n.T(ctx, key, replacements)
+	}
+	fallback := "Page {page} of {total}"
+	for name, value := range replacements {
+		fallback = strings.ReplaceAll(fallback, "{"+name+"}", fmt.Sprint(value))
+	}
+	return templ.Raw(templ.EscapeString(fallback))
+}
@@ -0,0 +1,146 @@
+package pagination
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+var (
+	// DefaultPerPage is the amount of items per page when the request doesn't specify one
+	DefaultPerPage = 20
+	// MaxPerPage caps the per_page param so clients can't request huge pages
+	MaxPerPage = 100
+)
+
+// Params are the pagination params of a request, either page based with `page` and `per_page`
+// or cursor based with `cursor` and `per_page`
+type Params struct {
+	Page    int
+	PerPage int
+	Cursor  string
+	url     *url.URL
+}
+
+// FromRequest parses the pagination params of the request query, invalid values fall back to the defaults
+func FromRequest(r *http.Request) Params {
+	query := r.URL.Query()
+	params := Params{
+		Page:    1,
+		PerPage: DefaultPerPage,
+		Cursor:  query.Get("cursor"),
+		url:     r.URL,
+	}
+	if page, err := strconv.Atoi(query.Get("page")); err == nil && page > 0 {
+		params.Page = page
+	}
+	if perPage, err := strconv.Atoi(query.Get("per_page")); err == nil && perPage > 0 {
+		params.PerPage = min(perPage, MaxPerPage)
+	}
+	return params
+}
+
+// Offset is the amount of items to skip to get to the page
+func (p Params) Offset() int {
+	return (max(p.Page, 1) - 1) * p.Limit()
+}
+
+// Limit is the amount of items of the page
+func (p Params) Limit() int {
+	if p.PerPage <= 0 {
+		return DefaultPerPage
+	}
+	return p.PerPage
+}
+
+// Info describes a page without its items, it's what the Pagination component renders
+type Info struct {
+	Page    int
+	PerPage int
+	// Total is the amount of items across all the pages, unknown when using cursors
+	Total      int
+	NextCursor string
+	PrevCursor string
+	url        *url.URL
+}
+
+// TotalPages returns the amount of pages, or 0 when the total is unknown
+func (i Info) TotalPages() int {
+	if i.PerPage <= 0 || i.Total <= 0 {
+		return 0
+	}
+	return (i.Total + i.PerPage - 1) / i.PerPage
+}
+
+func (i Info) HasNext() bool {
+	return i.NextCursor != "" || (i.Page > 0 && i.Page < i.TotalPages())
+}
+
+func (i Info) HasPrev() bool {
+	return i.PrevCursor != "" || i.Page > 1
+}
+
+// Href returns the url of the given page, keeping the rest of the query of the current request
+func (i Info) Href(page int) string {
+	return i.href(func(query url.Values) {
+		query.Del("cursor")
+		query.Set("page", strconv.Itoa(page))
+	})
+}
+
+// CursorHref returns the url of the page starting at the cursor, keeping the rest of the query of the current request
+func (i Info) CursorHref(cursor string) string {
+	return i.href(func(query url.Values) {
+		query.Del("page")
+		query.Set("cursor", cursor)
+	})
+}
+
+func (i Info) href(update func(query url.Values)) string {
+	query := url.Values{}
+	if i.url != nil {
+		query = i.url.Query()
+	}
+	update(query)
+	if i.PerPage != DefaultPerPage {
+		query.Set("per_page", strconv.Itoa(i.PerPage))
+	}
+	if i.url == nil {
+		return "?" + query.Encode()
+	}
+	u := *i.url
+	u.RawQuery = query.Encode()
+	return u.RequestURI()
+}
+
+// Page is a page of items together with the info needed to navigate to the others
+type Page[T any] struct {
+	Items []T
+	Info
+}
+
+// NewPage creates a page based result, total is the amount of items across all the pages
+func NewPage[T any](items []T, params Params, total int) Page[T] {
+	return Page[T]{
+		Items: items,
+		Info: Info{
+			Page:    max(params.Page, 1),
+			PerPage: params.Limit(),
+			Total:   total,
+			url:     params.url,
+		},
+	}
+}
+
+// NewCursorPage creates a cursor based result, the cursors are empty when there is no next or previous page
+func NewCursorPage[T any](items []T, params Params, next string, prev string) Page[T] {
+	return Page[T]{
+		Items: items,
+		Info: Info{
+			PerPage:    params.Limit(),
+			NextCursor: next,
+			PrevCursor: prev,
+			url:        params.url,
+		},
+	}
+}
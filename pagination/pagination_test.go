@@ -0,0 +1,77 @@
+package pagination
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/martinmunillas/otter/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected Params
+		offset   int
+	}{
+		{name: "defaults", target: "/items", expected: Params{Page: 1, PerPage: DefaultPerPage}, offset: 0},
+		{name: "page", target: "/items?page=3&per_page=10", expected: Params{Page: 3, PerPage: 10}, offset: 20},
+		{name: "invalid values", target: "/items?page=-1&per_page=abc", expected: Params{Page: 1, PerPage: DefaultPerPage}, offset: 0},
+		{name: "max per page", target: "/items?per_page=1000", expected: Params{Page: 1, PerPage: MaxPerPage}, offset: 0},
+		{name: "cursor", target: "/items?cursor=abc", expected: Params{Page: 1, PerPage: DefaultPerPage, Cursor: "abc"}, offset: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := FromRequest(httptest.NewRequest("GET", tt.target, nil))
+			params.url = nil
+			assert.Equal(t, tt.expected, params)
+			assert.Equal(t, tt.offset, params.Offset())
+		})
+	}
+}
+
+func TestPage(t *testing.T) {
+	params := FromRequest(httptest.NewRequest("GET", "/items?q=otter&page=2&per_page=10", nil))
+	page := NewPage([]string{"a", "b"}, params, 25)
+
+	assert.Equal(t, 3, page.TotalPages())
+	assert.True(t, page.HasPrev())
+	assert.True(t, page.HasNext())
+	assert.Equal(t, "/items?page=3&per_page=10&q=otter", page.Href(3))
+
+	cursorPage := NewCursorPage([]string{"a"}, params, "next", "")
+	assert.False(t, cursorPage.HasPrev())
+	assert.True(t, cursorPage.HasNext())
+	assert.Equal(t, "/items?cursor=next&per_page=10&q=otter", cursorPage.CursorHref("next"))
+}
+
+func TestLocalizedHrefs(t *testing.T) {
+	i18n.SetPrefixRouting(true)
+	t.Cleanup(func() { i18n.SetPrefixRouting(false) })
+
+	info := Info{Page: 2, PerPage: DefaultPerPage, Total: 100}
+	assert.Equal(t, "?page=1", prevHref(context.Background(), info))
+	assert.Equal(t, "?page=3", nextHref(context.Background(), info))
+}
+
+func TestPagination(t *testing.T) {
+	info := Info{Page: 2, PerPage: DefaultPerPage, Total: 100}
+
+	b := strings.Builder{}
+	err := Pagination(Props{Info: info}).Render(context.Background(), &b)
+	assert.NoError(t, err)
+	assert.Contains(t, b.String(), `hx-boost="true"`)
+	assert.Contains(t, b.String(), "Page 2 of 5")
+	assert.NotContains(t, b.String(), "hx-target")
+	assert.NotContains(t, b.String(), "hx-get")
+
+	b.Reset()
+	err = Pagination(Props{Info: info, Target: "#items"}).Render(context.Background(), &b)
+	assert.NoError(t, err)
+	assert.Contains(t, b.String(), `hx-target="#items"`)
+	assert.Contains(t, b.String(), `hx-get="?page=3"`)
+	assert.NotContains(t, b.String(), "hx-boost")
+}
@@ -1,30 +1,144 @@
 package send
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"sync"
 
 	"github.com/a-h/templ"
+	"github.com/martinmunillas/otter"
+	"github.com/martinmunillas/otter/log"
 )
 
+// maxPooledBufferSize avoids keeping the buffers of unusually big pages in the pool
+const maxPooledBufferSize = 1 << 20
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
 type htmlSender struct {
-	logger *slog.Logger
+	logger            *slog.Logger
+	buffered          bool
+	internalErrorPage templ.Component
+}
+
+// UseBuffering makes components render into memory before writing the response, so rendering errors
+// respond with a 500 status and the internal error page instead of a truncated page
+func (h *htmlSender) UseBuffering(enabled bool) {
+	h.buffered = enabled
+}
+
+// SetInternalErrorPage sets the page sent when a buffered component fails to render
+func (h *htmlSender) SetInternalErrorPage(component templ.Component) {
+	h.internalErrorPage = component
 }
 
 func (h *htmlSender) SetLogger(logger *slog.Logger) {
 	h.logger = logger
 }
 
+// getLogger returns the logger set with SetLogger or the otter default one
+func (h htmlSender) getLogger() *slog.Logger {
+	if h.logger != nil {
+		return h.logger
+	}
+	return log.Default()
+}
+
 func (h htmlSender) send(w http.ResponseWriter, ctx context.Context, component templ.Component, status int) {
-	w.WriteHeader(status)
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	}
 	if component == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	ctx, stream := otter.WithDeferredStream(ctx)
+	if h.buffered {
+		if !h.sendBuffered(w, ctx, component, status, stream) {
+			return
+		}
+	} else {
+		w.WriteHeader(status)
+		err := component.Render(ctx, w)
+		if err != nil {
+			h.getLogger().Error(err.Error())
+			return
+		}
+	}
+	h.streamDeferred(w, ctx, stream)
+}
+
+// streamDeferred writes the content of the Deferred components of the page as they resolve
+func (h htmlSender) streamDeferred(w http.ResponseWriter, ctx context.Context, stream *otter.DeferredStream) {
+	if stream.Len() == 0 {
 		return
 	}
-	err := component.Render(ctx, w)
+	controller := http.NewResponseController(w)
+	flush := func() error {
+		err := controller.Flush()
+		if errors.Is(err, http.ErrNotSupported) {
+			return nil
+		}
+		return err
+	}
+	err := flush()
+	if err == nil {
+		err = stream.Write(ctx, w, flush)
+	}
+	if err != nil {
+		h.getLogger().Error(err.Error())
+	}
+}
+
+// sendBuffered renders the component into memory before writing it, returns false if it failed to render
+func (h htmlSender) sendBuffered(w http.ResponseWriter, ctx context.Context, component templ.Component, status int, stream *otter.DeferredStream) bool {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledBufferSize {
+			bufferPool.Put(buf)
+		}
+	}()
+
+	rendered := true
+	err := component.Render(ctx, buf)
+	if err != nil {
+		rendered = false
+		h.getLogger().Error(err.Error())
+		status = http.StatusInternalServerError
+		buf.Reset()
+		if h.internalErrorPage != nil {
+			err = h.internalErrorPage.Render(ctx, buf)
+			if err != nil {
+				h.getLogger().Error(err.Error())
+				buf.Reset()
+			}
+		}
+		if buf.Len() == 0 {
+			buf.WriteString(http.StatusText(status))
+		}
+	}
+
+	// the length is unknown when deferred content is streamed after the page
+	if !rendered || stream.Len() == 0 {
+		w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	}
+	w.WriteHeader(status)
+	_, err = w.Write(buf.Bytes())
 	if err != nil {
-		h.logger.Error(err.Error())
+		h.getLogger().Error(err.Error())
+		return false
 	}
+	return rendered
 }
 
 func (h htmlSender) Ok(w http.ResponseWriter, ctx context.Context, component templ.Component) {
@@ -33,7 +147,7 @@ func (h htmlSender) Ok(w http.ResponseWriter, ctx context.Context, component tem
 
 func (h htmlSender) InternalError(w http.ResponseWriter, ctx context.Context, err error, component templ.Component) {
 	if err != nil {
-		h.logger.Error(err.Error())
+		h.getLogger().Error(err.Error())
 	}
 	h.send(w, ctx, component, http.StatusInternalServerError)
 }
@@ -0,0 +1,89 @@
+package send
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/martinmunillas/otter"
+	"github.com/stretchr/testify/assert"
+)
+
+func text(value string) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, value)
+		return err
+	})
+}
+
+// failing writes part of the page before failing to render
+func failing() templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, _ = io.WriteString(w, "<main>partial")
+		return errors.New("query failed")
+	})
+}
+
+func bufferedSender() htmlSender {
+	return htmlSender{
+		logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		buffered: true,
+	}
+}
+
+func TestBufferedHtml(t *testing.T) {
+	w := httptest.NewRecorder()
+	bufferedSender().Ok(w, context.Background(), text("<main>otter</main>"))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "<main>otter</main>", w.Body.String())
+	assert.Equal(t, strconv.Itoa(len("<main>otter</main>")), w.Header().Get("Content-Length"))
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+}
+
+func TestBufferedHtmlKeepsContentType(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Type", "image/svg+xml")
+	bufferedSender().Ok(w, context.Background(), text("<svg></svg>"))
+
+	assert.Equal(t, "image/svg+xml", w.Header().Get("Content-Type"))
+}
+
+func TestBufferedHtmlRenderError(t *testing.T) {
+	w := httptest.NewRecorder()
+	bufferedSender().Ok(w, context.Background(), failing())
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "Internal Server Error", w.Body.String())
+	assert.NotContains(t, w.Body.String(), "partial")
+	assert.Equal(t, strconv.Itoa(w.Body.Len()), w.Header().Get("Content-Length"))
+
+	sender := bufferedSender()
+	sender.SetInternalErrorPage(text("<h1>Something went wrong</h1>"))
+	w = httptest.NewRecorder()
+	sender.Ok(w, context.Background(), failing())
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "<h1>Something went wrong</h1>", w.Body.String())
+}
+
+func TestBufferedHtmlDeferred(t *testing.T) {
+	w := httptest.NewRecorder()
+	page := otter.Deferred(text("loading"), func(ctx context.Context) templ.Component {
+		return text("resolved")
+	})
+	bufferedSender().Ok(w, context.Background(), page)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	// the length is unknown as the deferred content is streamed after the page
+	assert.Empty(t, w.Header().Get("Content-Length"))
+	assert.True(t, w.Flushed)
+	assert.Contains(t, w.Body.String(), "loading")
+	assert.Contains(t, w.Body.String(), "resolved")
+}
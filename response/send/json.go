@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+
+	"github.com/martinmunillas/otter/log"
 )
 
 type jsonSender struct {
-	logger *slog.Logger
+	logger         *slog.Logger
+	problemDetails bool
 }
 type errorResponse struct {
 	Error errorMessage `json:"error"`
@@ -17,24 +20,39 @@ func (j *jsonSender) SetLogger(logger *slog.Logger) {
 	j.logger = logger
 }
 
+// getLogger returns the logger set with SetLogger or the otter default one
+func (j jsonSender) getLogger() *slog.Logger {
+	if j.logger != nil {
+		return j.logger
+	}
+	return log.Default()
+}
+
 func (j jsonSender) sendError(w http.ResponseWriter, errResponse errorResponse) {
+	if j.problemDetails {
+		j.Problem(w, Problem{
+			Status: errResponse.Error.Code,
+			Detail: errResponse.Error.Message,
+		})
+		return
+	}
 	w.WriteHeader(errResponse.Error.Code)
 	err := json.NewEncoder(w).Encode(errResponse)
 	if err != nil {
-		j.logger.Error(err.Error())
+		j.getLogger().Error(err.Error())
 	}
 }
 
 func (j jsonSender) Ok(w http.ResponseWriter, response any) {
 	err := json.NewEncoder(w).Encode(response)
 	if err != nil {
-		j.logger.Error(err.Error())
+		j.getLogger().Error(err.Error())
 	}
 }
 
 func (j jsonSender) InternalError(w http.ResponseWriter, err error) {
 	if err != nil {
-		j.logger.Error(err.Error())
+		j.getLogger().Error(err.Error())
 	}
 	j.sendError(w, errorResponse{
 		Error: errorMessage{
@@ -0,0 +1,49 @@
+package send
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Problem is an RFC 7807 problem details error response
+type Problem struct {
+	// Type is an URI identifying the kind of problem, "about:blank" by default
+	Type string `json:"type"`
+	// Title is a short summary of the kind of problem, the status text by default
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Errors maps the invalid fields to their error messages
+	Errors map[string][]string `json:"errors,omitempty"`
+}
+
+// UseProblemDetails makes the error responses use the problem details format (application/problem+json)
+// instead of the default {"error": {"code", "message"}} one
+func (j *jsonSender) UseProblemDetails(enabled bool) {
+	j.problemDetails = enabled
+}
+
+// ProblemDetails reports whether the error responses use the problem details format
+func (j jsonSender) ProblemDetails() bool {
+	return j.problemDetails
+}
+
+// Problem sends a problem details error response
+func (j jsonSender) Problem(w http.ResponseWriter, problem Problem) {
+	if problem.Status == 0 {
+		problem.Status = http.StatusInternalServerError
+	}
+	if problem.Type == "" {
+		problem.Type = "about:blank"
+	}
+	if problem.Title == "" {
+		problem.Title = http.StatusText(problem.Status)
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	err := json.NewEncoder(w).Encode(problem)
+	if err != nil {
+		j.getLogger().Error(err.Error())
+	}
+}
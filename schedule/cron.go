@@ -0,0 +1,177 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSpec is a parsed five field cron expression: minute, hour, day of month, month and day of week
+type cronSpec struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+	// anyDay is true when either the day of month or the day of week is `*`,
+	// if both are restricted a day matches when any of them matches
+	anyDay bool
+}
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	minuteField     = cronField{name: "minute", min: 0, max: 59}
+	hourField       = cronField{name: "hour", min: 0, max: 23}
+	dayOfMonthField = cronField{name: "day of month", min: 1, max: 31}
+	monthField      = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dayOfWeekField = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+func parseCron(expression string) (cronSpec, error) {
+	if descriptor, ok := descriptors[strings.ToLower(strings.TrimSpace(expression))]; ok {
+		expression = descriptor
+	}
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return cronSpec{}, fmt.Errorf("invalid cron expression \"%s\": expected 5 fields but got %d", expression, len(fields))
+	}
+
+	spec := cronSpec{}
+	var err error
+	if spec.minute, err = minuteField.parse(fields[0]); err != nil {
+		return cronSpec{}, fmt.Errorf("invalid cron expression \"%s\": %w", expression, err)
+	}
+	if spec.hour, err = hourField.parse(fields[1]); err != nil {
+		return cronSpec{}, fmt.Errorf("invalid cron expression \"%s\": %w", expression, err)
+	}
+	if spec.dayOfMonth, err = dayOfMonthField.parse(fields[2]); err != nil {
+		return cronSpec{}, fmt.Errorf("invalid cron expression \"%s\": %w", expression, err)
+	}
+	if spec.month, err = monthField.parse(fields[3]); err != nil {
+		return cronSpec{}, fmt.Errorf("invalid cron expression \"%s\": %w", expression, err)
+	}
+	if spec.dayOfWeek, err = dayOfWeekField.parse(fields[4]); err != nil {
+		return cronSpec{}, fmt.Errorf("invalid cron expression \"%s\": %w", expression, err)
+	}
+	// 7 is an alias for sunday
+	if spec.dayOfWeek&(1<<7) != 0 {
+		spec.dayOfWeek |= 1
+	}
+	spec.anyDay = strings.HasPrefix(fields[2], "*") || strings.HasPrefix(fields[4], "*")
+	return spec, nil
+}
+
+// parse returns a bitset with the values matched by the field, it supports lists, ranges, steps and names
+func (f cronField) parse(field string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step \"%s\" in %s field", stepPart, f.name)
+			}
+		}
+
+		start, end := f.min, f.max
+		if rangePart != "*" {
+			startPart, endPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			start, err = f.value(startPart)
+			if err != nil {
+				return 0, err
+			}
+			end = start
+			if isRange {
+				end, err = f.value(endPart)
+				if err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				end = f.max
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range \"%s\" in %s field", rangePart, f.name)
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value \"%s\" in %s field", s, f.name)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d-%d] in %s field", v, f.min, f.max, f.name)
+	}
+	return v, nil
+}
+
+func (s cronSpec) matchesDay(t time.Time) bool {
+	dom := s.dayOfMonth&(1<<t.Day()) != 0
+	dow := s.dayOfWeek&(1<<t.Weekday()) != 0
+	if s.anyDay {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// next returns the first time strictly after t that matches the spec, or the zero time if there is none
+func (s cronSpec) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// a matching time always exists within 5 years unless the spec is impossible, like the 31st of February
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.month&(1<<t.Month()) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<t.Hour()) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<t.Minute()) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,42 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCronNext(t *testing.T) {
+	from := time.Date(2025, time.January, 31, 10, 30, 15, 0, time.UTC)
+	testcases := []struct {
+		expression string
+		next       time.Time
+		err        bool
+	}{
+		{expression: "* * * * *", next: time.Date(2025, time.January, 31, 10, 31, 0, 0, time.UTC)},
+		{expression: "0 3 * * *", next: time.Date(2025, time.February, 1, 3, 0, 0, 0, time.UTC)},
+		{expression: "*/15 * * * *", next: time.Date(2025, time.January, 31, 10, 45, 0, 0, time.UTC)},
+		{expression: "0 9-17/4 * * mon-fri", next: time.Date(2025, time.January, 31, 13, 0, 0, 0, time.UTC)},
+		{expression: "0 0 1,15 * *", next: time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 * * 7", next: time.Date(2025, time.February, 2, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 13 * fri", next: time.Date(2025, time.February, 7, 0, 0, 0, 0, time.UTC)},
+		{expression: "@monthly", next: time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 29 feb *", next: time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 31 feb *", next: time.Time{}},
+		{expression: "0 3 * *", err: true},
+		{expression: "60 * * * *", err: true},
+		{expression: "*/0 * * * *", err: true},
+		{expression: "5-1 * * * *", err: true},
+	}
+
+	for _, testcase := range testcases {
+		spec, err := parseCron(testcase.expression)
+		if testcase.err {
+			assert.Error(t, err, testcase.expression)
+			continue
+		}
+		assert.NoError(t, err, testcase.expression)
+		assert.Equal(t, testcase.next, spec.next(from), testcase.expression)
+	}
+}
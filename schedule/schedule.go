@@ -0,0 +1,249 @@
+package schedule
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/martinmunillas/otter/log"
+	"github.com/martinmunillas/otter/utils"
+)
+
+type Task = func(ctx context.Context) error
+
+type entry struct {
+	name string
+	// named is set when the name is given through the Name option instead of defaulting to the schedule
+	named   bool
+	next    func(t time.Time) time.Time
+	task    Task
+	lockDB  *sql.DB
+	running atomic.Bool
+}
+
+var entries []*entry
+
+type Option func(e *entry)
+
+// Name identifies the task in the logs and in the single instance lock, by default the schedule is used as name.
+// Names must be unique
+func Name(name string) Option {
+	return func(e *entry) {
+		e.name = name
+		e.named = true
+	}
+}
+
+// SingleInstance makes sure only one instance of the app runs the task at the same time through a postgres
+// advisory lock, the task must have a Name as it's used as the lock key
+func SingleInstance(db *sql.DB) Option {
+	return func(e *entry) {
+		e.lockDB = db
+	}
+}
+
+func add(e *entry, options []Option) {
+	for _, option := range options {
+		option(e)
+	}
+	err := validate(e, entries)
+	if err != nil {
+		utils.Throw(err.Error())
+	}
+	entries = append(entries, e)
+}
+
+// validate makes sure the lock of single instance tasks isn't shared with other tasks,
+// as they would skip each other on every run
+func validate(e *entry, registered []*entry) error {
+	if e.lockDB != nil && !e.named {
+		return fmt.Errorf("single instance task `%s` needs a unique Name", e.name)
+	}
+	if !e.named {
+		return nil
+	}
+	for _, other := range registered {
+		if other.named && other.name == e.name {
+			return fmt.Errorf("task `%s` is already registered", e.name)
+		}
+	}
+	return nil
+}
+
+// Every registers a task to run on a fixed interval, starting one interval after the scheduler starts
+func Every(interval time.Duration, task Task, options ...Option) {
+	if interval <= 0 {
+		utils.Throw(fmt.Sprintf("invalid schedule interval %s, it must be positive", interval))
+	}
+	add(&entry{
+		name: fmt.Sprintf("every %s", interval),
+		next: func(t time.Time) time.Time { return t.Add(interval) },
+		task: task,
+	}, options)
+}
+
+// Cron registers a task to run on a cron schedule such as "0 3 * * *", the descriptors @hourly, @daily, @midnight,
+// @weekly, @monthly, @yearly and @annually are supported too. Times are evaluated in the local timezone
+func Cron(expression string, task Task, options ...Option) {
+	spec, err := parseCron(expression)
+	if err != nil {
+		utils.Throw(err.Error())
+	}
+	add(&entry{
+		name: expression,
+		next: spec.next,
+		task: task,
+	}, options)
+}
+
+type SchedulerOptions struct {
+	Logger *slog.Logger
+}
+
+type Scheduler struct {
+	options SchedulerOptions
+}
+
+// NewScheduler creates a scheduler for all the registered tasks
+func NewScheduler(options SchedulerOptions) *Scheduler {
+	if options.Logger == nil {
+		options.Logger = log.Default()
+	}
+	return &Scheduler{
+		options: options,
+	}
+}
+
+// Run schedules the tasks until the context is done, then waits for the running tasks to finish
+func (s *Scheduler) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, e := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, e, &wg)
+		}()
+	}
+	wg.Wait()
+	s.options.Logger.Info("Scheduler stopped")
+	return nil
+}
+
+func (s *Scheduler) loop(ctx context.Context, e *entry, wg *sync.WaitGroup) {
+	logger := s.options.Logger.With("task", e.name)
+	next := e.next(time.Now())
+	if next.IsZero() {
+		logger.Error("Task will never run, its schedule has no matching time")
+		return
+	}
+	logger.Debug(fmt.Sprintf("Task scheduled for %s", next.Format(time.RFC3339)))
+	for {
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		tick := next
+		next = e.next(tick)
+		if !e.running.CompareAndSwap(false, true) {
+			logger.Warn("Skipping run, the previous one is still running")
+		} else {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer e.running.Store(false)
+				s.run(ctx, e, logger, tick, next)
+			}()
+		}
+		if next.IsZero() {
+			return
+		}
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, e *entry, logger *slog.Logger, tick time.Time, next time.Time) {
+	// running tasks are allowed to finish when the scheduler is stopped
+	taskCtx := context.WithoutCancel(ctx)
+	if e.lockDB != nil {
+		unlock, locked, err := tryLock(ctx, e.lockDB, e.name)
+		if err != nil {
+			logger.Error(fmt.Sprintf("error acquiring task lock: %v", err))
+			return
+		}
+		if !locked {
+			logger.Debug("Skipping run, another instance is running the task")
+			return
+		}
+		defer unlock(holdUntil(tick, next))
+	}
+
+	logger.Info("Running task")
+	start := time.Now()
+	err := runTask(taskCtx, e.task)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Task failed: %v", err), "duration", time.Since(start))
+		return
+	}
+	logger.Info("Task ran successfully", "duration", time.Since(start))
+}
+
+func runTask(ctx context.Context, task Task) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task panicked: %v", r)
+		}
+	}()
+	return task(ctx)
+}
+
+// holdUntil returns until when the lock of a run is kept, so instances whose clocks are slightly behind
+// don't run the same tick again once the task finished
+func holdUntil(tick time.Time, next time.Time) time.Time {
+	hold := 30 * time.Second
+	if !next.IsZero() {
+		hold = min(hold, next.Sub(tick)/2)
+	}
+	return tick.Add(hold)
+}
+
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte("otter_schedule:" + name))
+	return int64(h.Sum64())
+}
+
+// tryLock acquires a session advisory lock on a dedicated connection, the returned function releases it
+// no earlier than the given time unless the context is done
+func tryLock(ctx context.Context, db *sql.DB, name string) (func(until time.Time), bool, error) {
+	stopped := ctx.Done()
+	ctx = context.WithoutCancel(ctx)
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	key := lockKey(name)
+	var locked bool
+	err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&locked)
+	if err != nil || !locked {
+		_ = conn.Close()
+		return nil, false, err
+	}
+	return func(until time.Time) {
+		timer := time.NewTimer(time.Until(until))
+		select {
+		case <-timer.C:
+		case <-stopped:
+			timer.Stop()
+		}
+		_, _ = conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", key)
+		_ = conn.Close()
+	}, true, nil
+}
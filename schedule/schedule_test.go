@@ -0,0 +1,28 @@
+package schedule
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	db := &sql.DB{}
+	entryWith := func(name string, options ...Option) *entry {
+		e := &entry{name: name}
+		for _, option := range options {
+			option(e)
+		}
+		return e
+	}
+	registered := []*entry{
+		entryWith("0 3 * * *"),
+		entryWith("cleanup", Name("cleanup")),
+	}
+
+	assert.NoError(t, validate(entryWith("0 3 * * *"), registered))
+	assert.NoError(t, validate(entryWith("0 3 * * *", Name("reports"), SingleInstance(db)), registered))
+	assert.Error(t, validate(entryWith("0 3 * * *", SingleInstance(db)), registered))
+	assert.Error(t, validate(entryWith("every 1h0m0s", Name("cleanup")), registered))
+}
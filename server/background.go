@@ -0,0 +1,14 @@
+package server
+
+import "context"
+
+// Worker is a long-running process that runs alongside the server, its context is cancelled when the server shuts down
+type Worker interface {
+	Run(ctx context.Context) error
+}
+
+// Background starts the workers when the server starts listening and waits for them to stop on shutdown
+func (s *Server) Background(workers ...Worker) *Server {
+	s.workers = append(s.workers, workers...)
+	return s
+}
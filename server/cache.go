@@ -0,0 +1,160 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/martinmunillas/otter"
+	"github.com/martinmunillas/otter/cache"
+	"github.com/martinmunillas/otter/i18n"
+	"github.com/martinmunillas/otter/server/tools"
+)
+
+type CacheOptions struct {
+	// TTL is how long the page is cached, forever until invalidated when zero
+	TTL time.Duration
+	// VaryQuery lists the query params that change the content of the page, the rest are ignored
+	VaryQuery []string
+	// VaryUser returns an identifier of the user the page is rendered for, or an empty string for anonymous users.
+	// When nil every user gets the same page, so requests carrying cookies other than the locale, theme and flash
+	// ones, like a session, skip the cache as their page could include the data of the signed in user
+	VaryUser func(r *http.Request) string
+	// Tags are used to invalidate the page through cache.Invalidate or Tools.InvalidateCache
+	Tags []string
+}
+
+// WithCache returns a copy of the page whose successful responses are cached, varying on the locale and theme of the request
+func (p Page) WithCache(options CacheOptions) Page {
+	p.Cache = &options
+	return p
+}
+
+// sharedCookies are the cookies set by otter, the page already varies on them
+var sharedCookies = []string{"otter-lang", "otter-theme", "otter-flash"}
+
+// cacheable reports whether the request can get a cached page, requests that could belong to a signed in user
+// are only cached when the page varies on the user
+func cacheable(r *http.Request, options *CacheOptions) bool {
+	if options.VaryUser != nil {
+		return true
+	}
+	for _, cookie := range r.Cookies() {
+		if !slices.Contains(sharedCookies, cookie.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+func cacheKey(r *http.Request, options *CacheOptions) string {
+	var key strings.Builder
+	fmt.Fprintf(&key, "%s %s|locale=%s|theme=%s", r.Method, r.URL.Path, i18n.FromCtx(r.Context()), otter.ThemeModeFromCtx(r.Context()))
+
+	query := r.URL.Query()
+	params := append([]string{}, options.VaryQuery...)
+	sort.Strings(params)
+	for _, param := range params {
+		fmt.Fprintf(&key, "|%s=%s", param, strings.Join(query[param], ","))
+	}
+	if options.VaryUser != nil {
+		fmt.Fprintf(&key, "|user=%s", options.VaryUser(r))
+	}
+	// partial responses don't include the layouts
+	if tools.IsPartial(r) {
+		fmt.Fprintf(&key, "|partial=%s", r.Header.Get("HX-Target"))
+	}
+	return key.String()
+}
+
+// responseRecorder keeps the response in memory so it can be cached
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+func etag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf("\"%s\"", hex.EncodeToString(sum[:16]))
+}
+
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func serveCached(w http.ResponseWriter, r *http.Request, options *CacheOptions, handle func(w http.ResponseWriter, r *http.Request)) {
+	if !cacheable(r, options) {
+		handle(w, r)
+		return
+	}
+	store := cache.Default()
+	key := cacheKey(r, options)
+	entry, ok := store.Get(key)
+	if !ok {
+		recorder := &responseRecorder{
+			header: http.Header{},
+			status: http.StatusOK,
+		}
+		handle(recorder, r)
+
+		// responses setting cookies belong to a single user so they are never cached
+		if recorder.status != http.StatusOK || recorder.header.Get("Set-Cookie") != "" {
+			for key, values := range recorder.header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(recorder.status)
+			_, _ = w.Write(recorder.body.Bytes())
+			return
+		}
+
+		entry = cache.Entry{
+			Body:   recorder.body.Bytes(),
+			Header: recorder.header,
+			ETag:   etag(recorder.body.Bytes()),
+			Tags:   options.Tags,
+		}
+		if options.TTL > 0 {
+			entry.Expires = time.Now().Add(options.TTL)
+		}
+		store.Set(key, entry)
+	}
+
+	for key, values := range entry.Header {
+		w.Header()[key] = slices.Clone(values)
+	}
+	w.Header().Set("ETag", entry.ETag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Add("Vary", "HX-Request, HX-Target")
+	if etagMatches(r.Header.Get("If-None-Match"), entry.ETag) {
+		w.Header().Del("Content-Length")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(entry.Body)
+}
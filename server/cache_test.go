@@ -0,0 +1,130 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/martinmunillas/otter/cache"
+	"github.com/martinmunillas/otter/server/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+// cachedServer serves a cached page at /posts counting how many times it's rendered
+func cachedServer(t *testing.T, options CacheOptions, handler Handler) (*Server, *int) {
+	previous := cache.Default()
+	cache.SetStore(cache.NewMemoryStore(10))
+	t.Cleanup(func() { cache.SetStore(previous) })
+
+	renders := 0
+	s := NewServer().Layout(namedLayout("root"))
+	s.HandlePages(NewPage("/posts", func(r *http.Request, t tools.Tools) {
+		renders++
+		handler(r, t)
+	}).WithCache(options))
+	return s, &renders
+}
+
+func okHandler(r *http.Request, t tools.Tools) {
+	t.Send.Ok.HTML(templ.Raw("posts"))
+}
+
+func TestCacheETag(t *testing.T) {
+	s, renders := cachedServer(t, CacheOptions{}, okHandler)
+
+	w := httptest.NewRecorder()
+	s.mux.ServeHTTP(w, httptest.NewRequest("GET", "/posts", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.HasPrefix(w.Body.String(), "<root>posts"))
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/posts", nil)
+	r.Header.Set("If-None-Match", etag)
+	s.mux.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Empty(t, w.Header().Get("Content-Length"))
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("GET", "/posts", nil)
+	r.Header.Set("If-None-Match", `"other"`)
+	s.mux.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.HasPrefix(w.Body.String(), "<root>posts"))
+
+	assert.Equal(t, 1, *renders)
+}
+
+func TestCacheSetCookie(t *testing.T) {
+	s, renders := cachedServer(t, CacheOptions{}, func(r *http.Request, t tools.Tools) {
+		t.SetCookie(http.Cookie{Name: "visited", Value: "true"})
+		okHandler(r, t)
+	})
+
+	for range 2 {
+		w := httptest.NewRecorder()
+		s.mux.ServeHTTP(w, httptest.NewRequest("GET", "/posts", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "visited=true", w.Header().Get("Set-Cookie"))
+		assert.Empty(t, w.Header().Get("ETag"))
+	}
+	assert.Equal(t, 2, *renders)
+}
+
+func TestCachePartial(t *testing.T) {
+	s, renders := cachedServer(t, CacheOptions{}, okHandler)
+
+	partial := func() *http.Request {
+		r := httptest.NewRequest("GET", "/posts", nil)
+		r.Header.Set("HX-Request", "true")
+		r.Header.Set("HX-Target", "main")
+		return r
+	}
+	for range 2 {
+		w := httptest.NewRecorder()
+		s.mux.ServeHTTP(w, partial())
+		assert.Equal(t, "posts", w.Body.String())
+
+		w = httptest.NewRecorder()
+		s.mux.ServeHTTP(w, httptest.NewRequest("GET", "/posts", nil))
+		assert.True(t, strings.HasPrefix(w.Body.String(), "<root>posts"))
+	}
+	assert.Equal(t, 2, *renders)
+}
+
+func TestCacheSession(t *testing.T) {
+	s, renders := cachedServer(t, CacheOptions{}, okHandler)
+	for range 2 {
+		r := httptest.NewRequest("GET", "/posts", nil)
+		r.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+		s.mux.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	assert.Equal(t, 2, *renders)
+
+	for range 2 {
+		r := httptest.NewRequest("GET", "/posts", nil)
+		r.AddCookie(&http.Cookie{Name: "otter-lang", Value: "en"})
+		s.mux.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	assert.Equal(t, 3, *renders)
+
+	s, renders = cachedServer(t, CacheOptions{VaryUser: func(r *http.Request) string {
+		cookie, err := r.Cookie("session")
+		if err != nil {
+			return ""
+		}
+		return cookie.Value
+	}}, okHandler)
+	for _, session := range []string{"abc", "abc", "def"} {
+		r := httptest.NewRequest("GET", "/posts", nil)
+		r.AddCookie(&http.Cookie{Name: "session", Value: session})
+		s.mux.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	assert.Equal(t, 2, *renders)
+}
@@ -1,11 +1,14 @@
 package server
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
 	"strconv"
 
+	"github.com/martinmunillas/otter/i18n"
+	"github.com/martinmunillas/otter/response/send"
 	"github.com/martinmunillas/otter/server/tools"
 )
 
@@ -32,18 +35,41 @@ func NewCommand[T any](
 func (c Command[T]) Handle(r *http.Request, t tools.Tools) {
 
 	if err := r.ParseForm(); err != nil {
-		t.Send.BadRequest.JSON("Invalid form data")
+		rejectInput(r, t, nil)
 		return
 	}
 	input := new(T)
-	err := parseFormIntoInput(r, input)
-	if err != nil {
-		t.Send.BadRequest.JSON("Invalid form data")
+	fieldErrors := parseFormIntoInput(r, input)
+	if len(fieldErrors) > 0 {
+		rejectInput(r, t, fieldErrors)
 		return
 	}
 	c.Handler(r, input, t)
 
 }
+
+// rejectInput responds with a bad request, with the reason of each invalid field when using problem details.
+// The form message is translated through the `otter.errors.invalidForm` key, the reasons through the key of each
+// fieldError
+func rejectInput(r *http.Request, t tools.Tools, fieldErrors map[string]error) {
+	message := i18n.TranslationOr(r.Context(), "otter.errors.invalidForm", "Invalid form data")
+	if !send.Json.ProblemDetails() {
+		t.Send.BadRequest.JSON(message)
+		return
+	}
+	errs := make(map[string][]string, len(fieldErrors))
+	for field, err := range fieldErrors {
+		reason := fieldError{key: "otter.errors.invalidField", fallback: "Invalid value"}
+		errors.As(err, &reason)
+		errs[field] = []string{i18n.TranslationOr(r.Context(), reason.key, reason.fallback)}
+	}
+	t.Send.Problem(send.Problem{
+		Status: http.StatusBadRequest,
+		Detail: message,
+		Errors: errs,
+	})
+}
+
 func (c Command[T]) GetID() string {
 	return c.ID
 }
@@ -78,16 +104,18 @@ type Commander interface {
 func (s *Server) HandleCommands(commands ...Commander) *Server {
 	for _, command := range commands {
 		s.mux.Handle(fmt.Sprintf("POST %s", CommandHref(command.GetID())), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = s.withLayouts(r)
 			command.Handle(r, tools.Make(w, r))
 		}))
 	}
 	return s
 }
 
-// parseFormIntoInput populates a struct from form values in the request.
-func parseFormIntoInput[T any](r *http.Request, input *T) error {
+// parseFormIntoInput populates a struct from form values in the request, returning the errors of the invalid fields.
+func parseFormIntoInput[T any](r *http.Request, input *T) map[string]error {
 	val := reflect.ValueOf(input).Elem()
 	typ := val.Type()
+	fieldErrors := map[string]error{}
 
 	for i := 0; i < typ.NumField(); i++ {
 		field := typ.Field(i)
@@ -103,11 +131,34 @@ func parseFormIntoInput[T any](r *http.Request, input *T) error {
 
 		err := setFieldValue(structField, formValue)
 		if err != nil {
-			return fmt.Errorf("error setting field %s: %v", field.Name, err)
+			fieldErrors[field.Name] = fmt.Errorf("error setting field %s: %w", field.Name, err)
 		}
 	}
 
-	return nil
+	return fieldErrors
+}
+
+// fieldError is the reason a form value is invalid, translated through the key when sent to the client
+type fieldError struct {
+	key      string
+	fallback string
+	err      error
+}
+
+func (e fieldError) Error() string {
+	return e.err.Error()
+}
+
+func (e fieldError) Unwrap() error {
+	return e.err
+}
+
+// parseError returns the reason the value couldn't be parsed, the key and fallback are used for invalid syntax
+func parseError(err error, key string, fallback string) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return fieldError{key: "otter.errors.outOfRange", fallback: "Value out of range", err: err}
+	}
+	return fieldError{key: key, fallback: fallback, err: err}
 }
 
 // setFieldValue sets a value in a reflect.Value based on its type.
@@ -120,27 +171,27 @@ func setFieldValue(field reflect.Value, value string) error {
 	case reflect.String:
 		field.SetString(value)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		intVal, err := strconv.ParseInt(value, 10, 64)
+		intVal, err := strconv.ParseInt(value, 10, field.Type().Bits())
 		if err != nil {
-			return err
+			return parseError(err, "otter.errors.invalidInteger", "Must be a whole number")
 		}
 		field.SetInt(intVal)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		uintVal, err := strconv.ParseUint(value, 10, 64)
+		uintVal, err := strconv.ParseUint(value, 10, field.Type().Bits())
 		if err != nil {
-			return err
+			return parseError(err, "otter.errors.invalidUnsigned", "Must be a positive whole number")
 		}
 		field.SetUint(uintVal)
 	case reflect.Float32, reflect.Float64:
-		floatVal, err := strconv.ParseFloat(value, 64)
+		floatVal, err := strconv.ParseFloat(value, field.Type().Bits())
 		if err != nil {
-			return err
+			return parseError(err, "otter.errors.invalidNumber", "Must be a number")
 		}
 		field.SetFloat(floatVal)
 	case reflect.Bool:
 		boolVal, err := strconv.ParseBool(value)
 		if err != nil {
-			return err
+			return fieldError{key: "otter.errors.invalidBoolean", fallback: "Must be true or false", err: err}
 		}
 		field.SetBool(boolVal)
 	default:
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/martinmunillas/otter/i18n"
+	"github.com/martinmunillas/otter/response/send"
+	"github.com/martinmunillas/otter/server/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+type commandInput struct {
+	Name   string
+	Age    int
+	Small  int8
+	Price  float64
+	Active bool
+}
+
+func TestCommandProblem(t *testing.T) {
+	send.Json.UseProblemDetails(true)
+	t.Cleanup(func() { send.Json.UseProblemDetails(false) })
+	i18n.AddLocaleBytes("en", []byte(`{"otter": {"errors": {"invalidInteger": "Must be an integer"}}}`))
+
+	handled := false
+	s := NewServer().HandleCommands(NewCommand("save", func(r *http.Request, input *commandInput, t tools.Tools) {
+		handled = true
+	}))
+
+	form := url.Values{
+		"Name":   {"otter"},
+		"Age":    {"old"},
+		"Small":  {"1000"},
+		"Price":  {"free"},
+		"Active": {"maybe"},
+	}
+	r := httptest.NewRequest("POST", CommandHref("save"), strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	s.mux.ServeHTTP(w, r)
+
+	assert.False(t, handled)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+
+	problem := send.Problem{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, send.Problem{
+		Type:     "about:blank",
+		Title:    "Bad Request",
+		Status:   http.StatusBadRequest,
+		Detail:   "Invalid form data",
+		Instance: "/commands/save",
+		Errors: map[string][]string{
+			"Age":    {"Must be an integer"},
+			"Small":  {"Value out of range"},
+			"Price":  {"Must be a number"},
+			"Active": {"Must be true or false"},
+		},
+	}, problem)
+}
@@ -0,0 +1,17 @@
+package server
+
+import (
+	"github.com/martinmunillas/otter/email"
+	"github.com/martinmunillas/otter/gallery"
+)
+
+// handleDevTools adds the pages only available while running under `otter dev`
+func (s *Server) handleDevTools() {
+	outbox := email.OutboxHandler(email.DevOutboxDir)
+	s.mux.Handle("GET /_otter/mail", outbox)
+	s.mux.Handle("GET /_otter/mail/{id}", outbox)
+
+	components := gallery.Handler()
+	s.mux.Handle("GET /_otter/components", components)
+	s.mux.Handle("GET /_otter/components/{story}/{variant}", components)
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/martinmunillas/otter/server/tools"
+)
+
+type Layout = tools.Layout
+
+type prefixLayout struct {
+	prefix string
+	layout Layout
+}
+
+// Layout sets the root layout, it wraps every full page response of the server
+func (s *Server) Layout(layout Layout) *Server {
+	s.layout = layout
+	return s
+}
+
+// PrefixLayout adds a layout nested inside the root layout for every path under the given prefix, the prefix
+// /admin matches /admin and /admin/users but not /administrators. Layouts with shorter prefixes wrap the ones with longer prefixes
+func (s *Server) PrefixLayout(prefix string, layout Layout) *Server {
+	s.prefixLayouts = append(s.prefixLayouts, prefixLayout{
+		prefix: prefix,
+		layout: layout,
+	})
+	sort.SliceStable(s.prefixLayouts, func(i, j int) bool {
+		return len(s.prefixLayouts[i].prefix) < len(s.prefixLayouts[j].prefix)
+	})
+	return s
+}
+
+func (s *Server) withLayouts(r *http.Request, layouts ...Layout) *http.Request {
+	chain := make([]Layout, 0, len(s.prefixLayouts)+len(layouts)+1)
+	if s.layout != nil {
+		chain = append(chain, s.layout)
+	}
+	for _, l := range s.prefixLayouts {
+		if matchesPrefix(r.URL.Path, l.prefix) {
+			chain = append(chain, l.layout)
+		}
+	}
+	chain = append(chain, layouts...)
+	if len(chain) == 0 {
+		return r
+	}
+	return r.WithContext(tools.WithLayouts(r.Context(), chain...))
+}
+
+// matchesPrefix reports whether the path is the prefix or is under it, matching whole segments only
+func matchesPrefix(path string, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/")
+}
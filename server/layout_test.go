@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/martinmunillas/otter/server/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+func namedLayout(name string) Layout {
+	return func(ctx context.Context, content templ.Component) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			_, _ = io.WriteString(w, "<"+name+">")
+			err := content.Render(ctx, w)
+			_, _ = io.WriteString(w, "</"+name+">")
+			return err
+		})
+	}
+}
+
+func TestLayoutChain(t *testing.T) {
+	s := NewServer().
+		Layout(namedLayout("root")).
+		PrefixLayout("/admin/users", namedLayout("users")).
+		PrefixLayout("/admin", namedLayout("admin")).
+		PrefixLayout("/blog", namedLayout("blog"))
+	page := NewPage("/admin/users/{id}", nil).
+		WithLayout(namedLayout("page")).
+		WithLayout(namedLayout("details"))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/admin/users/1", nil)
+	page.Handler = func(r *http.Request, t tools.Tools) {
+		t.Send.Ok.HTML(templ.Raw("content"))
+	}
+	s.HandlePages(page)
+	s.mux.ServeHTTP(w, r)
+
+	assert.True(t, strings.HasPrefix(w.Body.String(), "<root><admin><users><page><details>content</details></page></users></admin>"))
+	assert.NotContains(t, w.Body.String(), "<blog>")
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("GET", "/admin/users/1", nil)
+	r.Header.Set("HX-Request", "true")
+	r.Header.Set("HX-Target", "main")
+	s.mux.ServeHTTP(w, r)
+	assert.Equal(t, "content", w.Body.String())
+}
+
+func TestLayoutPrefixSegments(t *testing.T) {
+	s := NewServer().PrefixLayout("/admin", namedLayout("admin"))
+	s.HandlePages(
+		NewPage("/admin", func(r *http.Request, t tools.Tools) {
+			t.Send.Ok.HTML(templ.Raw("admin"))
+		}),
+		NewPage("/administrators", func(r *http.Request, t tools.Tools) {
+			t.Send.Ok.HTML(templ.Raw("administrators"))
+		}),
+		NewPage("/admin-help", func(r *http.Request, t tools.Tools) {
+			t.Send.Ok.HTML(templ.Raw("help"))
+		}),
+	)
+
+	testcases := []struct {
+		path   string
+		layout bool
+	}{
+		{path: "/admin", layout: true},
+		{path: "/administrators", layout: false},
+		{path: "/admin-help", layout: false},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			s.mux.ServeHTTP(w, httptest.NewRequest("GET", testcase.path, nil))
+			assert.Equal(t, testcase.layout, strings.Contains(w.Body.String(), "<admin>"))
+		})
+	}
+}
@@ -1,6 +1,12 @@
 package server
 
-import "net/http"
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/martinmunillas/otter/log"
+)
 
 type Middleware = func(next http.Handler) http.Handler
 
@@ -8,3 +14,31 @@ func (s *Server) Use(middleware Middleware) *Server {
 	s.middlewares = append(s.middlewares, middleware)
 	return s
 }
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// LogRequests is a middleware that logs every request through the otter default logger, Listen wraps the server with it
+func LogRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		log.Default().Info(
+			fmt.Sprintf("%s %s", r.Method, r.URL.Path),
+			"status", recorder.status,
+			"duration", time.Since(start),
+		)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/martinmunillas/otter"
 	"github.com/martinmunillas/otter/server/tools"
 )
 
@@ -12,6 +13,9 @@ type Handler = func(r *http.Request, t tools.Tools)
 type Page struct {
 	Path    string
 	Handler Handler
+	// Layouts are nested inside the server and prefix layouts, the first one being the outermost
+	Layouts []Layout
+	Cache   *CacheOptions
 }
 
 func NewPage(path string, handler Handler) Page {
@@ -21,9 +25,23 @@ func NewPage(path string, handler Handler) Page {
 	}
 }
 
+// WithLayout returns a copy of the page with the layout nested inside its current layouts
+func (p Page) WithLayout(layout Layout) Page {
+	p.Layouts = append(p.Layouts[:len(p.Layouts):len(p.Layouts)], layout)
+	return p
+}
+
 func (s *Server) HandlePages(pages ...Page) *Server {
 	for _, page := range pages {
 		s.mux.Handle(fmt.Sprintf("GET %s", page.Path), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = s.withLayouts(r, page.Layouts...)
+			// pages showing flashed toasts belong to a single user
+			if page.Cache != nil && len(otter.FlashedToasts(r.Context())) == 0 {
+				serveCached(w, r, page.Cache, func(w http.ResponseWriter, r *http.Request) {
+					page.Handler(r, tools.Make(w, r))
+				})
+				return
+			}
 			page.Handler(r, tools.Make(w, r))
 		}))
 	}
@@ -3,9 +3,10 @@ package server
 import (
 	"fmt"
 	"log"
-	"log/slog"
 	"net/http"
 	"path/filepath"
+
+	otterlog "github.com/martinmunillas/otter/log"
 )
 
 func (s *Server) ServeStatic(path string) *Server {
@@ -16,6 +17,6 @@ func (s *Server) ServeStatic(path string) *Server {
 
 	s.mux.Handle("/static/", http.StripPrefix("/static", http.FileServer(http.Dir(staticDir))))
 
-	slog.Info(fmt.Sprintf("Serving static files from %s", staticDir))
+	otterlog.Default().Info(fmt.Sprintf("Serving static files from %s", staticDir))
 	return s
 }
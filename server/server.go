@@ -1,17 +1,29 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/martinmunillas/otter"
 	"github.com/martinmunillas/otter/i18n"
+	"github.com/martinmunillas/otter/log"
 )
 
+const shutdownTimeout = 10 * time.Second
+
 type Server struct {
-	mux         *http.ServeMux
-	middlewares []Middleware
+	mux           *http.ServeMux
+	middlewares   []Middleware
+	layout        Layout
+	prefixLayouts []prefixLayout
+	workers       []Worker
 }
 
 func NewServer() *Server {
@@ -21,19 +33,56 @@ func NewServer() *Server {
 }
 
 func (s *Server) Listen(port int64) {
+	logger := log.Default()
 	isDevServer := os.Getenv("OTTER_DEV_SERVER") == "true"
 	if isDevServer {
-		slog.Info(fmt.Sprintf("Server listening on http://localhost:%d", port+1))
+		s.handleDevTools()
+		logger.Info(fmt.Sprintf("Server listening on http://localhost:%d", port+1))
+		logger.Info(fmt.Sprintf("Mail outbox on http://localhost:%d/_otter/mail", port+1))
+		logger.Info(fmt.Sprintf("Component gallery on http://localhost:%d/_otter/components", port+1))
 	} else {
-		slog.Info(fmt.Sprintf("Server listening on port %d", port))
+		logger.Info(fmt.Sprintf("Server listening on port %d", port))
 	}
 
-	handler := i18n.Middleware(s.mux)
+	handler := otter.FlashMiddleware(otter.ThemeMiddleware(i18n.Middleware(s.mux)))
 	for _, middleware := range s.middlewares {
 		handler = middleware(handler)
 	}
-	err := http.ListenAndServe(PortString(port), handler)
-	if err != nil {
-		slog.Error(err.Error())
+	handler = LogRequests(handler)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var wg sync.WaitGroup
+	for _, worker := range s.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := worker.Run(ctx)
+			if err != nil {
+				logger.Error(err.Error())
+			}
+		}()
+	}
+
+	httpServer := &http.Server{
+		Addr:    PortString(port),
+		Handler: handler,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		err := httpServer.Shutdown(shutdownCtx)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+	}()
+
+	err := httpServer.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error(err.Error())
 	}
+	stop()
+	wg.Wait()
 }
@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/martinmunillas/otter"
+)
+
+func isHTMXRequest(r *http.Request) bool {
+	return r.Header.Get("HX-Request") == "true"
+}
+
+// setToast triggers the toast through htmx when possible, otherwise it's flashed until the next full page load
+func setToast(w http.ResponseWriter, r *http.Request, toast otter.Toast) {
+	toast = toast.Translate(r.Context())
+	if !isHTMXRequest(r) {
+		_ = otter.AddFlash(w, r, toast)
+		return
+	}
+	_ = appendToastTrigger(w.Header(), "HX-Trigger", toast)
+}
+
+// flashTriggeredToasts moves the toasts triggered through htmx into the flash,
+// as the current response is not going to be swapped in by the client
+func flashTriggeredToasts(w http.ResponseWriter, r *http.Request) {
+	header := w.Header()
+	events, err := parseTriggerHeader(header.Get("HX-Trigger"))
+	if err != nil {
+		return
+	}
+	raw, ok := events["makeToast"]
+	if !ok || len(raw) == 0 {
+		return
+	}
+	trigger := toastTrigger{}
+	err = json.Unmarshal(raw, &trigger)
+	if err != nil {
+		return
+	}
+	toasts := make([]otter.Toast, 0, len(trigger.Toasts))
+	for _, item := range trigger.Toasts {
+		toast := otter.Toast{}
+		err = json.Unmarshal(item, &toast)
+		if err != nil {
+			return
+		}
+		toasts = append(toasts, toast)
+	}
+
+	delete(events, "makeToast")
+	err = setTriggerHeader(header, "HX-Trigger", events)
+	if err != nil {
+		return
+	}
+	_ = otter.AddFlash(w, r, toasts...)
+}
@@ -0,0 +1,145 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+type HX struct {
+	// Location does a client-side redirect without a full page reload
+	Location func(path string)
+	// PushURL pushes a new url into the browser history
+	PushURL func(url string)
+	// ReplaceURL replaces the current url in the browser location bar
+	ReplaceURL func(url string)
+	// Refresh makes the client do a full refresh of the page
+	Refresh func()
+	// Retarget updates the target of the content update to a different element through a CSS selector
+	Retarget func(selector string)
+	// Reswap changes how the response will be swapped, see https://htmx.org/attributes/hx-swap/
+	Reswap func(swap string)
+	// Reselect chooses which part of the response is used to be swapped in through a CSS selector
+	Reselect func(selector string)
+	// Trigger triggers a client-side event as soon as the response is received
+	Trigger func(event string, detail any)
+	// TriggerAfterSettle triggers a client-side event after the settling step
+	TriggerAfterSettle func(event string, detail any)
+	// TriggerAfterSwap triggers a client-side event after the swap step
+	TriggerAfterSwap func(event string, detail any)
+}
+
+func makeHX(w http.ResponseWriter) HX {
+	header := w.Header()
+	return HX{
+		Location: func(path string) {
+			header.Set("HX-Location", path)
+		},
+		PushURL: func(url string) {
+			header.Set("HX-Push-Url", url)
+		},
+		ReplaceURL: func(url string) {
+			header.Set("HX-Replace-Url", url)
+		},
+		Refresh: func() {
+			header.Set("HX-Refresh", "true")
+		},
+		Retarget: func(selector string) {
+			header.Set("HX-Retarget", selector)
+		},
+		Reswap: func(swap string) {
+			header.Set("HX-Reswap", swap)
+		},
+		Reselect: func(selector string) {
+			header.Set("HX-Reselect", selector)
+		},
+		Trigger: func(event string, detail any) {
+			_ = mergeTrigger(header, "HX-Trigger", event, detail)
+		},
+		TriggerAfterSettle: func(event string, detail any) {
+			_ = mergeTrigger(header, "HX-Trigger-After-Settle", event, detail)
+		},
+		TriggerAfterSwap: func(event string, detail any) {
+			_ = mergeTrigger(header, "HX-Trigger-After-Swap", event, detail)
+		},
+	}
+}
+
+// mergeTrigger adds the event to the trigger header keeping the events already set on it,
+// the new detail replaces the previous one when the event was already triggered
+func mergeTrigger(header http.Header, key string, event string, detail any) error {
+	events, err := parseTriggerHeader(header.Get(key))
+	if err != nil {
+		return err
+	}
+	value, err := json.Marshal(detail)
+	if err != nil {
+		return err
+	}
+	events[event] = value
+	return setTriggerHeader(header, key, events)
+}
+
+// toastTrigger is the detail of the makeToast event, htmx only hands objects over
+// as the event detail and wraps anything else as {value: detail}
+type toastTrigger struct {
+	Toasts []json.RawMessage `json:"toasts"`
+}
+
+// appendToastTrigger adds the toast to the makeToast event, keeping the toasts already triggered
+func appendToastTrigger(header http.Header, key string, toast any) error {
+	events, err := parseTriggerHeader(header.Get(key))
+	if err != nil {
+		return err
+	}
+	value, err := json.Marshal(toast)
+	if err != nil {
+		return err
+	}
+	trigger := toastTrigger{}
+	if previous, exists := events["makeToast"]; exists && string(previous) != "null" {
+		err = json.Unmarshal(previous, &trigger)
+		if err != nil {
+			return err
+		}
+	}
+	trigger.Toasts = append(trigger.Toasts, value)
+	events["makeToast"], err = json.Marshal(trigger)
+	if err != nil {
+		return err
+	}
+	return setTriggerHeader(header, key, events)
+}
+
+func setTriggerHeader(header http.Header, key string, events map[string]json.RawMessage) error {
+	if len(events) == 0 {
+		header.Del(key)
+		return nil
+	}
+	data, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	header.Set(key, string(data))
+	return nil
+}
+
+// parseTriggerHeader reads both the JSON and the comma separated forms of the trigger headers
+func parseTriggerHeader(value string) (map[string]json.RawMessage, error) {
+	events := map[string]json.RawMessage{}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return events, nil
+	}
+	if strings.HasPrefix(value, "{") {
+		err := json.Unmarshal([]byte(value), &events)
+		return events, err
+	}
+	for _, event := range strings.Split(value, ",") {
+		event = strings.TrimSpace(event)
+		if event != "" {
+			events[event] = json.RawMessage("null")
+		}
+	}
+	return events, nil
+}
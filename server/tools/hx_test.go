@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/martinmunillas/otter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeTrigger(t *testing.T) {
+	testcases := []struct {
+		initial string
+		event   string
+		detail  any
+		out     string
+	}{
+		{
+			initial: "",
+			event:   "saved",
+			detail:  nil,
+			out:     `{"saved":null}`,
+		},
+		{
+			initial: "first, second",
+			event:   "third",
+			detail:  "value",
+			out:     `{"first":null,"second":null,"third":"value"}`,
+		},
+		{
+			initial: `{"saved":{"id":1}}`,
+			event:   "saved",
+			detail:  map[string]int{"id": 2},
+			out:     `{"saved":{"id":2}}`,
+		},
+	}
+
+	for _, testcase := range testcases {
+		header := http.Header{}
+		if testcase.initial != "" {
+			header.Set("HX-Trigger", testcase.initial)
+		}
+		err := mergeTrigger(header, "HX-Trigger", testcase.event, testcase.detail)
+		assert.NoError(t, err)
+		assert.JSONEq(t, testcase.out, header.Get("HX-Trigger"))
+	}
+}
+
+func TestAppendToastTrigger(t *testing.T) {
+	testcases := []struct {
+		initial string
+		toast   any
+		out     string
+	}{
+		{
+			initial: "",
+			toast:   map[string]string{"level": "info"},
+			out:     `{"makeToast":{"toasts":[{"level":"info"}]}}`,
+		},
+		{
+			initial: `{"makeToast":{"toasts":[{"level":"info"}]},"saved":null}`,
+			toast:   map[string]string{"level": "danger"},
+			out:     `{"makeToast":{"toasts":[{"level":"info"},{"level":"danger"}]},"saved":null}`,
+		},
+	}
+
+	for _, testcase := range testcases {
+		header := http.Header{}
+		if testcase.initial != "" {
+			header.Set("HX-Trigger", testcase.initial)
+		}
+		err := appendToastTrigger(header, "HX-Trigger", testcase.toast)
+		assert.NoError(t, err)
+		assert.JSONEq(t, testcase.out, header.Get("HX-Trigger"))
+	}
+}
+
+func TestSetToast(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+
+	setToast(w, r, otter.Toast{Level: otter.SUCCESS, Message: "Saved"})
+	setToast(w, r, otter.Toast{Level: otter.DANGER, Message: "Failed"})
+
+	assert.Equal(t, `{"makeToast":{"toasts":[{"level":"success","message":"Saved"},{"level":"danger","message":"Failed"}]}}`, w.Header().Get("HX-Trigger"))
+}
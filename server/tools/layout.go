@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/a-h/templ"
+	"github.com/martinmunillas/otter"
+)
+
+// Layout wraps the content of a page into a bigger component, usually the html document or a shared shell
+type Layout = func(ctx context.Context, content templ.Component) templ.Component
+
+type layoutsKeyType string
+
+var layoutsKey layoutsKeyType = "layouts"
+
+// WithLayouts returns a copy of the context with the given layouts appended to the layout chain,
+// the first layout of the chain is the outermost one
+func WithLayouts(ctx context.Context, layouts ...Layout) context.Context {
+	if len(layouts) == 0 {
+		return ctx
+	}
+	current := layoutsFromCtx(ctx)
+	chain := make([]Layout, 0, len(current)+len(layouts))
+	chain = append(chain, current...)
+	chain = append(chain, layouts...)
+	return context.WithValue(ctx, layoutsKey, chain)
+}
+
+func layoutsFromCtx(ctx context.Context) []Layout {
+	l, ok := ctx.Value(layoutsKey).([]Layout)
+	if !ok {
+		return nil
+	}
+	return l
+}
+
+// IsPartial reports whether the request only expects a fragment of the page,
+// that is an htmx request that is not a boosted navigation nor a history restore
+func IsPartial(r *http.Request) bool {
+	if r.Header.Get("HX-Request") != "true" {
+		return false
+	}
+	if r.Header.Get("HX-History-Restore-Request") == "true" {
+		return false
+	}
+	if r.Header.Get("HX-Boosted") == "true" {
+		target := r.Header.Get("HX-Target")
+		return target != "" && target != "body"
+	}
+	return true
+}
+
+// withLayouts wraps the component into the layout chain of the request unless it's a partial request.
+// The toast handler and the modal root are added at the end of the outermost layout content
+func withLayouts(r *http.Request, component templ.Component) templ.Component {
+	if component == nil || IsPartial(r) {
+		return component
+	}
+	ctx := r.Context()
+	layouts := layoutsFromCtx(ctx)
+	if len(layouts) == 0 {
+		return component
+	}
+	for i := len(layouts) - 1; i >= 0; i-- {
+		if i == 0 {
+			component = templ.Join(component, otter.ToastHandler(), otter.ModalRoot())
+		}
+		component = layouts[i](ctx, component)
+	}
+	return component
+}
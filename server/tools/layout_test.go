@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPartial(t *testing.T) {
+	testcases := []struct {
+		name    string
+		headers map[string]string
+		partial bool
+	}{
+		{name: "full page load", headers: map[string]string{}, partial: false},
+		{name: "htmx request", headers: map[string]string{"HX-Request": "true"}, partial: true},
+		{name: "htmx request with target", headers: map[string]string{"HX-Request": "true", "HX-Target": "list"}, partial: true},
+		{name: "history restore", headers: map[string]string{"HX-Request": "true", "HX-History-Restore-Request": "true"}, partial: false},
+		{name: "boosted navigation", headers: map[string]string{"HX-Request": "true", "HX-Boosted": "true"}, partial: false},
+		{name: "boosted into body", headers: map[string]string{"HX-Request": "true", "HX-Boosted": "true", "HX-Target": "body"}, partial: false},
+		{name: "boosted into element", headers: map[string]string{"HX-Request": "true", "HX-Boosted": "true", "HX-Target": "main"}, partial: true},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			for key, value := range testcase.headers {
+				r.Header.Set(key, value)
+			}
+			assert.Equal(t, testcase.partial, IsPartial(r))
+		})
+	}
+}
+
+func wrapper(name string) Layout {
+	return func(ctx context.Context, content templ.Component) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			_, _ = io.WriteString(w, "<"+name+">")
+			err := content.Render(ctx, w)
+			_, _ = io.WriteString(w, "</"+name+">")
+			return err
+		})
+	}
+}
+
+func TestWithLayouts(t *testing.T) {
+	content := templ.Raw("content")
+
+	r := httptest.NewRequest("GET", "/", nil)
+	ctx := WithLayouts(r.Context(), wrapper("root"), wrapper("section"))
+	ctx = WithLayouts(ctx, wrapper("page"))
+	r = r.WithContext(ctx)
+
+	b := strings.Builder{}
+	err := withLayouts(r, content).Render(ctx, &b)
+	assert.NoError(t, err)
+	html := b.String()
+	assert.True(t, strings.HasPrefix(html, "<root><section><page>content</page></section>"))
+	assert.True(t, strings.HasSuffix(html, "</root>"))
+	// the toast handler and modal root are inside the outermost layout, after the nested ones
+	toasts := strings.Index(html, `id="toast-container"`)
+	modal := strings.Index(html, `id="otter-modal-root"`)
+	assert.Greater(t, toasts, strings.Index(html, "</section>"))
+	assert.Greater(t, modal, toasts)
+
+	r.Header.Set("HX-Request", "true")
+	b.Reset()
+	err = withLayouts(r, content).Render(ctx, &b)
+	assert.NoError(t, err)
+	assert.Equal(t, "content", b.String())
+}
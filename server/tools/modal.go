@@ -0,0 +1,30 @@
+package tools
+
+import (
+	"net/http"
+
+	"github.com/a-h/templ"
+	"github.com/martinmunillas/otter"
+	"github.com/martinmunillas/otter/response/send"
+)
+
+type Modal struct {
+	// Open shows the component, usually an otter.Modal or otter.ConfirmModal, without swapping the request target
+	Open func(component templ.Component)
+	// Close closes the open modal
+	Close func()
+}
+
+func makeModal(w http.ResponseWriter, r *http.Request) Modal {
+	return Modal{
+		Open: func(component templ.Component) {
+			header := w.Header()
+			header.Set("HX-Reswap", "none")
+			_ = mergeTrigger(header, "HX-Trigger-After-Settle", "otter:openModal", nil)
+			send.Html.Ok(w, r.Context(), otter.ModalSwap(component))
+		},
+		Close: func() {
+			_ = mergeTrigger(w.Header(), "HX-Trigger", "otter:closeModal", nil)
+		},
+	}
+}
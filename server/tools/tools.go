@@ -1,13 +1,14 @@
 package tools
 
 import (
-	"encoding/json"
 	"net/http"
 	"time"
 
 	"github.com/a-h/templ"
 	"github.com/martinmunillas/otter"
+	"github.com/martinmunillas/otter/cache"
 	"github.com/martinmunillas/otter/i18n"
+	"github.com/martinmunillas/otter/jobs"
 	"github.com/martinmunillas/otter/response/send"
 )
 
@@ -44,6 +45,8 @@ type Send struct {
 	BadRequest    SendBadRequest
 	InternalError SendInternalError
 	NotModified   func()
+	// Problem sends an RFC 7807 problem details response, the instance defaults to the request path
+	Problem func(problem send.Problem)
 }
 
 type Redirect struct {
@@ -57,13 +60,26 @@ type Tools struct {
 	Translation   func(key string) string
 	ErrorT        func(key string) error
 	DateTime      func(t time.Time, style i18n.DateStyle) string
+	Number        func(value any) string
+	Currency      func(value any, code string) string
+	Percent       func(value any) string
+	Compact       func(value float64) string
 	Send          Send
 	Redirect      Redirect
+	HX            HX
+	Modal         Modal
 	SetRawCookies func(rawCookies string)
 	SetCookie     func(cookie http.Cookie)
-	SetToast      func(toast otter.Toast)
-	AddHeader     func(key string, value string)
-	DelHeader     func(key string)
+	// SetToast shows the toast through htmx, or on the next full page load when the request doesn't come from htmx
+	SetToast func(toast otter.Toast)
+	// FlashToast shows the toast on the next full page load
+	FlashToast func(toast otter.Toast)
+	// InvalidateCache removes the cached pages that have any of the tags
+	InvalidateCache func(tags ...string)
+	// Enqueue adds a background job, see the jobs package
+	Enqueue   func(name string, payload any, options ...jobs.Option) error
+	AddHeader func(key string, value string)
+	DelHeader func(key string)
 }
 
 func Make(w http.ResponseWriter, r *http.Request) Tools {
@@ -80,6 +96,16 @@ func Make(w http.ResponseWriter, r *http.Request) Tools {
 		DateTime: func(t time.Time, style i18n.DateStyle) string {
 			return i18n.DateTime(ctx, t, style)
 		},
+		Number: func(value any) string { return i18n.Number(ctx, value) },
+		Currency: func(value any, code string) string {
+			return i18n.Currency(ctx, value, code)
+		},
+		Percent:         func(value any) string { return i18n.Percent(ctx, value) },
+		Compact:         func(value float64) string { return i18n.Compact(ctx, value) },
+		InvalidateCache: cache.Invalidate,
+		Enqueue: func(name string, payload any, options ...jobs.Option) error {
+			return jobs.Enqueue(ctx, name, payload, options...)
+		},
 		AddHeader: func(key string, value string) {
 			w.Header().Add(key, value)
 		},
@@ -88,9 +114,11 @@ func Make(w http.ResponseWriter, r *http.Request) Tools {
 		},
 		Redirect: Redirect{
 			Server: func(path string, status int) {
+				flashTriggeredToasts(w, r)
 				http.Redirect(w, r, path, status)
 			},
 			HX: func(path string) {
+				flashTriggeredToasts(w, r)
 				w.Header().Set("HX-Redirect", path)
 			},
 		},
@@ -100,19 +128,18 @@ func Make(w http.ResponseWriter, r *http.Request) Tools {
 		SetCookie: func(cookie http.Cookie) {
 			http.SetCookie(w, &cookie)
 		},
+		HX:    makeHX(w),
+		Modal: makeModal(w, r),
 		SetToast: func(toast otter.Toast) {
-			eventMap := map[string]otter.Toast{}
-			eventMap["makeToast"] = toast
-			jsonData, err := json.Marshal(eventMap)
-			if err != nil {
-				return
-			}
-			w.Header().Set("HX-Trigger", string(jsonData))
+			setToast(w, r, toast)
+		},
+		FlashToast: func(toast otter.Toast) {
+			_ = otter.AddFlash(w, r, toast)
 		},
 		Send: Send{
 			Ok: SendOk{
 				HTML: func(component templ.Component) {
-					send.Html.Ok(w, ctx, component)
+					send.Html.Ok(w, ctx, withLayouts(r, component))
 				},
 				JSON: func(content any) {
 					send.Json.Ok(w, content)
@@ -120,7 +147,7 @@ func Make(w http.ResponseWriter, r *http.Request) Tools {
 			},
 			Unauthorized: SendUnauthorized{
 				HTML: func(component templ.Component) {
-					send.Html.Unauthorized(w, ctx, component)
+					send.Html.Unauthorized(w, ctx, withLayouts(r, component))
 				},
 				JSON: func(message string) {
 					send.Json.Unauthorized(w, message)
@@ -128,7 +155,7 @@ func Make(w http.ResponseWriter, r *http.Request) Tools {
 			},
 			Forbidden: SendForbidden{
 				HTML: func(component templ.Component) {
-					send.Html.Forbidden(w, ctx, component)
+					send.Html.Forbidden(w, ctx, withLayouts(r, component))
 				},
 				JSON: func(message string) {
 					send.Json.Forbidden(w, message)
@@ -136,7 +163,7 @@ func Make(w http.ResponseWriter, r *http.Request) Tools {
 			},
 			NotFound: SendNotFound{
 				HTML: func(component templ.Component) {
-					send.Html.NotFound(w, ctx, component)
+					send.Html.NotFound(w, ctx, withLayouts(r, component))
 				},
 				JSON: func(message string) {
 					send.Json.NotFound(w, message)
@@ -144,7 +171,7 @@ func Make(w http.ResponseWriter, r *http.Request) Tools {
 			},
 			BadRequest: SendBadRequest{
 				HTML: func(component templ.Component) {
-					send.Html.BadRequest(w, ctx, component)
+					send.Html.BadRequest(w, ctx, withLayouts(r, component))
 				},
 				JSON: func(message string) {
 					send.Json.BadRequest(w, message)
@@ -152,7 +179,7 @@ func Make(w http.ResponseWriter, r *http.Request) Tools {
 			},
 			InternalError: SendInternalError{
 				HTML: func(err error, component templ.Component) {
-					send.Html.InternalError(w, ctx, err, component)
+					send.Html.InternalError(w, ctx, err, withLayouts(r, component))
 				},
 				JSON: func(err error) {
 					send.Json.InternalError(w, err)
@@ -161,6 +188,12 @@ func Make(w http.ResponseWriter, r *http.Request) Tools {
 			NotModified: func() {
 				w.WriteHeader(http.StatusNotModified)
 			},
+			Problem: func(problem send.Problem) {
+				if problem.Instance == "" {
+					problem.Instance = r.URL.Path
+				}
+				send.Json.Problem(w, problem)
+			},
 		},
 	}
 }
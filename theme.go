@@ -0,0 +1,181 @@
+package otter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type Colors struct {
+	Background        string
+	Foreground        string
+	Muted             string
+	MutedForeground   string
+	Border            string
+	Primary           string
+	PrimaryForeground string
+	Info              string
+	Success           string
+	Warning           string
+	Danger            string
+	DangerForeground  string
+}
+
+// Theme holds the design tokens used by the components, exposed as CSS variables by ThemeStyles
+type Theme struct {
+	Light Colors
+	Dark  Colors
+	// Radius of the corners of buttons, inputs, alerts and dialogs
+	Radius string
+	// Spacing is the base unit of the gaps and paddings
+	Spacing  string
+	FontSans string
+	FontMono string
+}
+
+var DefaultTheme = Theme{
+	Light: Colors{
+		Background:        "#ffffff",
+		Foreground:        "#18181b",
+		Muted:             "#f4f4f5",
+		MutedForeground:   "#71717a",
+		Border:            "#e4e4e7",
+		Primary:           "#2563eb",
+		PrimaryForeground: "#ffffff",
+		Info:              "#0284c7",
+		Success:           "#16a34a",
+		Warning:           "#d97706",
+		Danger:            "#dc2626",
+		DangerForeground:  "#ffffff",
+	},
+	Dark: Colors{
+		Background:        "#18181b",
+		Foreground:        "#fafafa",
+		Muted:             "#27272a",
+		MutedForeground:   "#a1a1aa",
+		Border:            "#3f3f46",
+		Primary:           "#3b82f6",
+		PrimaryForeground: "#ffffff",
+		Info:              "#38bdf8",
+		Success:           "#4ade80",
+		Warning:           "#fbbf24",
+		Danger:            "#ef4444",
+		DangerForeground:  "#ffffff",
+	},
+	Radius:   "0.25rem",
+	Spacing:  "0.25rem",
+	FontSans: "system-ui, -apple-system, 'Segoe UI', Roboto, sans-serif",
+	FontMono: "ui-monospace, SFMono-Regular, Menlo, monospace",
+}
+
+// cssValue keeps the token from closing the style element
+func cssValue(value string) string {
+	return strings.NewReplacer("<", "", ";", "", "{", "", "}", "").Replace(value)
+}
+
+// CSSVariables returns the declarations of the color tokens as CSS variables, like `--danger: #dc2626;`
+func (c Colors) CSSVariables() string {
+	var b strings.Builder
+	for _, variable := range []struct{ name, value string }{
+		{"background", c.Background},
+		{"foreground", c.Foreground},
+		{"muted", c.Muted},
+		{"muted-foreground", c.MutedForeground},
+		{"border", c.Border},
+		{"primary", c.Primary},
+		{"primary-foreground", c.PrimaryForeground},
+		{"info", c.Info},
+		{"success", c.Success},
+		{"warning", c.Warning},
+		{"danger", c.Danger},
+		{"danger-foreground", c.DangerForeground},
+	} {
+		fmt.Fprintf(&b, "--%s: %s;", variable.name, cssValue(variable.value))
+	}
+	return b.String()
+}
+
+// CSSVariables returns the declarations of the tokens shared by the light and dark variants as CSS variables
+func (t Theme) CSSVariables() string {
+	return fmt.Sprintf("--radius: %s;--spacing: %s;--font-sans: %s;--font-mono: %s;",
+		cssValue(t.Radius), cssValue(t.Spacing), cssValue(t.FontSans), cssValue(t.FontMono))
+}
+
+type ThemeMode string
+
+const (
+	ThemeSystem ThemeMode = "system"
+	ThemeLight  ThemeMode = "light"
+	ThemeDark   ThemeMode = "dark"
+)
+
+const themeCookieName = "otter-theme"
+
+type themeKeyType string
+
+var themeKey themeKeyType = "theme"
+
+func parseThemeMode(value string) ThemeMode {
+	switch ThemeMode(value) {
+	case ThemeLight, ThemeDark:
+		return ThemeMode(value)
+	default:
+		return ThemeSystem
+	}
+}
+
+// ThemeMiddleware reads the theme mode chosen by the user and handles the /set-theme requests sent by ThemeToggle
+func ThemeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/set-theme" {
+			w.Header().Add("HX-Refresh", "true")
+			SetTheme(w, parseThemeMode(r.FormValue("theme")))
+			return
+		}
+		mode := ThemeSystem
+		cookie, err := r.Cookie(themeCookieName)
+		if err == nil {
+			mode = parseThemeMode(cookie.Value)
+		}
+		next.ServeHTTP(w, r.WithContext(WithThemeMode(r.Context(), mode)))
+	})
+}
+
+func SetTheme(w http.ResponseWriter, mode ThemeMode) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     themeCookieName,
+		Value:    string(mode),
+		Path:     "/",
+		MaxAge:   60 * 60 * 24 * 365,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// WithThemeMode returns a copy of the context using the given theme mode
+func WithThemeMode(ctx context.Context, mode ThemeMode) context.Context {
+	return context.WithValue(ctx, themeKey, mode)
+}
+
+// ThemeModeFromCtx returns the theme mode chosen by the user, ThemeSystem when there is none
+func ThemeModeFromCtx(ctx context.Context) ThemeMode {
+	mode, ok := ctx.Value(themeKey).(ThemeMode)
+	if !ok {
+		return ThemeSystem
+	}
+	return mode
+}
+
+// themeCSS returns the stylesheet of the theme for the mode, following prefers-color-scheme for ThemeSystem
+func themeCSS(theme Theme, mode ThemeMode) string {
+	shared := theme.CSSVariables()
+	switch mode {
+	case ThemeLight:
+		return fmt.Sprintf(":root{color-scheme: light;%s%s}", shared, theme.Light.CSSVariables())
+	case ThemeDark:
+		return fmt.Sprintf(":root{color-scheme: dark;%s%s}", shared, theme.Dark.CSSVariables())
+	default:
+		return fmt.Sprintf(":root{color-scheme: light dark;%s%s}@media (prefers-color-scheme: dark){:root{%s}}",
+			shared, theme.Light.CSSVariables(), theme.Dark.CSSVariables())
+	}
+}
@@ -8,7 +8,15 @@ package otter
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/martinmunillas/otter/i18n"
+)
 
 const (
 	INFO    = "info"
@@ -17,13 +25,28 @@ const (
 	DANGER  = "danger"
 )
 
+type ToastAction struct {
+	Label string `json:"label"`
+	// URL is requested with hx-post when the action is clicked
+	URL string `json:"url"`
+}
+
 type Toast struct {
 	Level   string `json:"level"`
 	Message string `json:"message"`
+	Title   string `json:"title,omitempty"`
+	// Duration before the toast is dismissed, the configured default when zero and never when negative
+	Duration time.Duration `json:"duration,omitempty"`
+	// Persistent toasts have no close button, so the zero value can always be dismissed
+	Persistent bool         `json:"persistent,omitempty"`
+	Action     *ToastAction `json:"action,omitempty"`
+	// MessageKey and TitleKey are translation keys resolved in the locale of the request that sets the toast
+	MessageKey string `json:"-"`
+	TitleKey   string `json:"-"`
 }
 
 func newToast(level string, message string) Toast {
-	return Toast{level, message}
+	return Toast{Level: level, Message: message}
 }
 
 func InfoToast(message string) Toast {
@@ -42,78 +65,448 @@ func DangerToast(message string) Toast {
 	return newToast(DANGER, message)
 }
 
+// TranslatedToast creates a toast whose message is the translation of the key in the request locale
+func TranslatedToast(level string, key string) Toast {
+	toast := newToast(level, "")
+	toast.MessageKey = key
+	return toast
+}
+
+func (t Toast) WithTitle(title string) Toast {
+	t.Title = title
+	return t
+}
+
+func (t Toast) WithDuration(duration time.Duration) Toast {
+	t.Duration = duration
+	return t
+}
+
+// WithAction adds a button to the toast that posts to the url, like an undo
+func (t Toast) WithAction(label string, url string) Toast {
+	t.Action = &ToastAction{Label: label, URL: url}
+	return t
+}
+
+// Translate resolves the translation keys of the toast in the locale of the context
+func (t Toast) Translate(ctx context.Context) Toast {
+	if t.MessageKey != "" {
+		t.Message = i18n.Translation(ctx, t.MessageKey)
+		t.MessageKey = ""
+	}
+	if t.TitleKey != "" {
+		t.Title = i18n.Translation(ctx, t.TitleKey)
+		t.TitleKey = ""
+	}
+	return t
+}
+
+type ToastPosition string
+
+const (
+	TopLeft      ToastPosition = "top-left"
+	TopCenter    ToastPosition = "top-center"
+	TopRight     ToastPosition = "top-right"
+	BottomLeft   ToastPosition = "bottom-left"
+	BottomCenter ToastPosition = "bottom-center"
+	BottomRight  ToastPosition = "bottom-right"
+)
+
+type ToastOptions struct {
+	// Position of the toasts on the screen, TopRight by default
+	Position ToastPosition
+	// MaxStack is the amount of toasts shown at the same time, the oldest ones are dismissed first. 5 by default
+	MaxStack int
+	// Duration of the toasts that don't set one, 5 seconds by default
+	Duration time.Duration
+}
+
+var toastOptions = ToastOptions{
+	Position: TopRight,
+	MaxStack: 5,
+	Duration: 5 * time.Second,
+}
+
+// ConfigureToasts changes how the ToastHandler presents the toasts, zero values keep the defaults
+func ConfigureToasts(options ToastOptions) {
+	if options.Position != "" {
+		toastOptions.Position = options.Position
+	}
+	if options.MaxStack > 0 {
+		toastOptions.MaxStack = options.MaxStack
+	}
+	if options.Duration != 0 {
+		toastOptions.Duration = options.Duration
+	}
+}
+
+func toastPositionClass() templ.CSSClass {
+	position := toastOptions.Position
+	vertical, horizontal, _ := strings.Cut(string(position), "-")
+	style := fmt.Sprintf("position:fixed;z-index:1000;display:flex;flex-direction:%s;gap:0.5rem;padding:1rem;%s:0;",
+		map[string]string{"top": "column", "bottom": "column-reverse"}[vertical], vertical)
+	switch horizontal {
+	case "left":
+		style += "left:0;"
+	case "center":
+		style += "left:50%;transform:translateX(-50%);"
+	default:
+		style += "right:0;"
+	}
+	id := "otter-toasts-" + string(position)
+	return templ.ComponentCSSClass{
+		ID:    id,
+		Class: templ.SafeCSS(fmt.Sprintf(".%s{%s}", id, style)),
+	}
+}
+
 func (t Toast) String() string {
 	return fmt.Sprintf("%s: %s", t.Level, t.Message)
 }
 
+// toastDuration is the duration of the toast in milliseconds for the script, empty to use the configured one
+func toastDuration(t Toast) string {
+	if t.Duration == 0 {
+		return ""
+	}
+	if t.Duration < 0 {
+		return "-1"
+	}
+	return strconv.FormatInt(t.Duration.Milliseconds(), 10)
+}
+
+func toastRole(t Toast) string {
+	if t.Level == DANGER {
+		return "alert"
+	}
+	return "status"
+}
+
+// pendingToasts are the flashed toasts rendered by the ToastHandler, the newest ones up to the stack limit
+func pendingToasts(ctx context.Context) []Toast {
+	toasts := FlashedToasts(ctx)
+	if len(toasts) > toastOptions.MaxStack {
+		toasts = toasts[len(toasts)-toastOptions.MaxStack:]
+	}
+	return toasts
+}
+
+// toast renders the same markup the script builds for the toasts triggered through htmx,
+// so flashed toasts are shown without javascript
+func toast(t Toast) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var1 == nil {
+			templ_7745c5c3_Var1 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		var templ_7745c5c3_Var2 = []any{"toast", "toast-" + t.Level}
+		templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var2...)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var3 string
+		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(templ.CSSClasses(templ_7745c5c3_Var2).String())
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `toast.templ`, Line: 1, Col: 0}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "\" role=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var4 string
+		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(toastRole(t))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `toast.templ`, Line: 191, Col: 21}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if toastDuration(t) != "" {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, " data-duration=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var5 string
+			templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(toastDuration(t))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `toast.templ`, Line: 193, Col: 35}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		if t.Persistent {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, " data-persistent")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "><div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if t.Title != "" {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "<strong class=\"toast-title\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var6 string
+			templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(t.Title)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `toast.templ`, Line: 201, Col: 41}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "</strong> ")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "<span>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var7 string
+		templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(t.Message)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `toast.templ`, Line: 203, Col: 20}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "</span></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if t.Action != nil {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "<form method=\"post\" action=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var8 templ.SafeURL = templ.SafeURL(t.Action.URL)
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(string(templ_7745c5c3_Var8)))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "\"><button type=\"submit\" class=\"toast-action\" hx-post=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var9 string
+			templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(t.Action.URL)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `toast.templ`, Line: 207, Col: 69}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var10 string
+			templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(t.Action.Label)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `toast.templ`, Line: 207, Col: 88}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "</button></form>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "</div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
 func toastListener() templ.ComponentScript {
 	return templ.ComponentScript{
-		Name: `__templ_toastListener_013f`,
-		Function: `function __templ_toastListener_013f(){class Toast {
+		Name: `__templ_toastListener_59ef`,
+		Function: `function __templ_toastListener_59ef(){const container = document.querySelector("#toast-container");
+	const maxStack = Number(container.dataset.maxStack) || 5;
+	const defaultDuration = Number(container.dataset.duration) || 5000;
+
+	/**
+	* Makes the button that dismisses the toast.
+	* @param toast {HTMLDivElement}
+	* @returns {HTMLButtonElement}
+	*/
+	function makeCloseButton(toast) {
+		const button = document.createElement("button");
+		button.type = "button";
+		button.classList.add("toast-close");
+		button.setAttribute("aria-label", container.dataset.closeLabel);
+		button.innerHTML = "&times;";
+		button.addEventListener("click", () => toast.remove());
+		return button;
+	}
+
+	/**
+	* Adds the close button and the dismiss timer to a toast, either built by the script or rendered by the server.
+	* @param toast {HTMLDivElement}
+	* @param duration {number} milliseconds before it's dismissed, never when not positive
+	* @param persistent {boolean}
+	*/
+	function enhance(toast, duration, persistent) {
+		toast.querySelector(".toast-action")?.addEventListener("click", () => toast.remove());
+		if (!persistent) {
+			toast.appendChild(makeCloseButton(toast));
+		}
+		if (duration > 0) {
+			setTimeout(() => toast.remove(), duration);
+		}
+	}
+
+	class Toast {
 		/**
 		* A class representing a Toast notification.
-		* @param level {("info"|"success"|"warning"|"danger")}
-		* @param message { string }
+		* @param detail {{level: ("info"|"success"|"warning"|"danger"), message: string, title?: string, duration?: number, persistent?: boolean, action?: {label: string, url: string}}}
+		* the duration is in nanoseconds, as sent by the server
 		*/
-		constructor(level, message) {
-			this.level = level;
-			this.message = message;
+		constructor(detail) {
+			this.level = detail.level;
+			this.message = detail.message;
+			this.title = detail.title;
+			this.duration = detail.duration ? detail.duration / 1e6 : defaultDuration;
+			this.persistent = detail.persistent;
+			this.action = detail.action;
 		}
 
 		/**
-		* Makes the toast container element. A button containing the entire notification.
-		* @returns {HTMLButtonElement}
+		* Makes the toast element, danger toasts are announced right away while the rest wait for the user to be idle.
+		* @returns {HTMLDivElement}
 		*/
-		#makeToastContainerButton() {
-			const button = document.createElement("button");
-			button.classList.add("toast");
-			button.classList.add(` + "`" + `toast-${this.level}` + "`" + `);
-			button.setAttribute("role", "alert");
-			button.setAttribute("aria-label", "Close");
-			button.addEventListener("click", () => button.remove());
-			return button;
+		#makeToastElement() {
+			const toast = document.createElement("div");
+			toast.classList.add("toast");
+			toast.classList.add(` + "`" + `toast-${this.level}` + "`" + `);
+			toast.setAttribute("role", this.level === "danger" ? "alert" : "status");
+			return toast;
 		}
 
 		/**
-		* Makes the element containing the body of the toast notification.
-		* @returns {HTMLSpanElement}
+		* Makes the element containing the title and message of the toast notification.
+		* @returns {HTMLDivElement}
 		*/
 		#makeToastContentElement() {
-			const messageContainer = document.createElement("span");
-			messageContainer.textContent = this.message;
-			return messageContainer;
+			const content = document.createElement("div");
+			if (this.title) {
+				const title = document.createElement("strong");
+				title.classList.add("toast-title");
+				title.textContent = this.title;
+				content.appendChild(title);
+			}
+			const message = document.createElement("span");
+			message.textContent = this.message;
+			content.appendChild(message);
+			return content;
 		}
 
 		/**
-		* Presents the toast notification at the end of the given container.
-		* @param containerQuerySelector {string} a CSS query selector identifying the container for all toasts.
+		* Makes the button that posts the action.
+		* @returns {HTMLButtonElement}
 		*/
-		show(containerQuerySelector = "#toast-container") {
-			const toast = this.#makeToastContainerButton();
-			const toastContent = this.#makeToastContentElement()
-			toast.appendChild(toastContent);
+		#makeActionButton() {
+			const button = document.createElement("button");
+			button.type = "button";
+			button.classList.add("toast-action");
+			button.textContent = this.action.label;
+			button.setAttribute("hx-post", this.action.url);
+			if (window.htmx) {
+				htmx.process(button);
+			}
+			return button;
+		}
 
-			const toastContainer = document.querySelector(containerQuerySelector);
-			toastContainer.appendChild(toast);
+		/**
+		* Presents the toast notification at the end of the container, dismissing the oldest ones over the stack limit.
+		*/
+		show() {
+			const toast = this.#makeToastElement();
+			toast.appendChild(this.#makeToastContentElement());
+			if (this.action) {
+				toast.appendChild(this.#makeActionButton());
+			}
+
+			while (container.children.length >= maxStack) {
+				container.firstElementChild.remove();
+			}
+			container.appendChild(toast);
+			enhance(toast, this.duration, this.persistent);
 		}
 	}
 
 	document.body.addEventListener("makeToast", onMakeToast);
 
+	// the flashed toasts are rendered by the server
+	for (const toast of container.querySelectorAll(".toast")) {
+		const duration = toast.dataset.duration ? Number(toast.dataset.duration) : defaultDuration;
+		enhance(toast, duration, toast.hasAttribute("data-persistent"));
+	}
+
 	/**
-	* Presents a toast notification when the ` + "`" + `makeToast` + "`" + ` event is triggered
-	* @param e {{detail: {level: string, message: string}}}
+	* Presents the toast notifications when the ` + "`" + `makeToast` + "`" + ` event is triggered
+	* @param e {{detail: {toasts: object[]}}}
 	*/
 	function onMakeToast(e) {
-		const toast = new Toast(e.detail.level, e.detail.message);
-		toast.show();
+		for (const detail of e.detail.toasts || []) {
+			new Toast(detail).show();
+		}
 	}
 }`,
-		Call:       templ.SafeScript(`__templ_toastListener_013f`),
-		CallInline: templ.SafeScriptInline(`__templ_toastListener_013f`),
+		Call:       templ.SafeScript(`__templ_toastListener_59ef`),
+		CallInline: templ.SafeScriptInline(`__templ_toastListener_59ef`),
 	}
 }
 
+// ToastHandler presents the toasts triggered through htmx and the ones flashed by previous requests,
+// configure it with ConfigureToasts. The flashed toasts are rendered by the server so they are shown without
+// javascript, the script only adds their close buttons and timers.
+// The close button is labeled with the `otter.toast.close` translation
 func ToastHandler() templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
@@ -130,12 +523,79 @@ func ToastHandler() templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var1 == nil {
-			templ_7745c5c3_Var1 = templ.NopComponent
+		templ_7745c5c3_Var11 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var11 == nil {
+			templ_7745c5c3_Var11 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div id=\"toast-container\"></div>")
+		var templ_7745c5c3_Var12 = []any{toastPositionClass()}
+		templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var12...)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "<div id=\"toast-container\" class=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var13 string
+		templ_7745c5c3_Var13, templ_7745c5c3_Err = templ.JoinStringErrs(templ.CSSClasses(templ_7745c5c3_Var12).String())
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `toast.templ`, Line: 1, Col: 0}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var13))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "\" aria-live=\"polite\" data-max-stack=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var14 string
+		templ_7745c5c3_Var14, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(toastOptions.MaxStack))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `toast.templ`, Line: 356, Col: 54}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var14))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "\" data-duration=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var15 string
+		templ_7745c5c3_Var15, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.FormatInt(toastOptions.Duration.Milliseconds(), 10))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `toast.templ`, Line: 357, Col: 77}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var15))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "\" data-close-label=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var16 string
+		templ_7745c5c3_Var16, templ_7745c5c3_Err = templ.JoinStringErrs(i18n.TranslationOr(ctx, "otter.toast.close", "Close"))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `toast.templ`, Line: 358, Col: 74}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var16))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		for _, t := range pendingToasts(ctx) {
+			templ_7745c5c3_Err = toast(t).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "</div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}